@@ -25,6 +25,15 @@ func main() {
 	var force bool
 	var ignorePatterns string
 	var ignoreFilePatterns string
+	var onlyPatterns string
+	var mode string
+	var threshold float64
+	var maxCommentRatio float64
+	var removeBlockComments bool
+	var removeDocComments bool
+	var noDefaultKeeps bool
+	var includeGenerated bool
+	var maxFunctionComments int
 	var configAdd string
 	var configAddGlobal string
 	var configAddFileIgnore string
@@ -43,6 +52,15 @@ func main() {
 	flag.BoolVar(&force, "f", false, "Run in non-git directories (shorthand)")
 	flag.StringVar(&ignorePatterns, "ignore", "", "Comma-separated list of regex patterns to preserve comments")
 	flag.StringVar(&ignoreFilePatterns, "ignore-file", "", "Comma-separated list of regex patterns to ignore files")
+	flag.StringVar(&onlyPatterns, "only", "", "Comma-separated list of regex patterns, or commented-code; only matching comments are removed")
+	flag.StringVar(&mode, "mode", config.ModeAll, "Which comments to remove: all, ai-only (AI narration), redundant (restates the code) or commented-code")
+	flag.Float64Var(&threshold, "threshold", 0.7, "Minimum score (0-1) for a comment to be removed in ai-only and redundant modes")
+	flag.BoolVar(&removeBlockComments, "remove-block-comments", false, "Also remove /* */ block comments (license headers and bundler annotations are kept)")
+	flag.BoolVar(&removeDocComments, "remove-doc-comments", false, "Also remove doc comments such as JSDoc, KDoc and /// (license headers are kept)")
+	flag.BoolVar(&noDefaultKeeps, "no-default-keeps", false, "Do not keep TODO, FIXME, NOTE, HACK, XXX, BUG and WARNING comments by default")
+	flag.BoolVar(&includeGenerated, "include-generated", false, "Also process generated, vendored and minified files (skipped by default)")
+	flag.Float64Var(&maxCommentRatio, "max-comment-ratio", 0, "Fail staged runs when more than this share (0-1) of added lines are comments, instead of removing them")
+	flag.IntVar(&maxFunctionComments, "max-comment-lines-per-function", 0, "Fail staged runs when a function gains more than this many comment lines, instead of removing them")
 	flag.StringVar(&configAdd, "add-ignore", "", "Add a regex pattern to the project's ignore list")
 	flag.StringVar(&configAddGlobal, "add-ignore-global", "", "Add a regex pattern to the global ignore list")
 	flag.StringVar(&configAddFileIgnore, "add-ignore-file", "", "Add a regex pattern to the local file ignore list")
@@ -91,7 +109,7 @@ func main() {
 
 	commentConfig := config.New()
 	err := commentConfig.LoadConfigurations()
-	if err != nil && verbose {
+	if err != nil {
 		fmt.Printf("Warning: Could not load configuration: %v\n", err)
 	}
 
@@ -159,6 +177,94 @@ func main() {
 		}
 	}
 
+	if onlyPatterns == config.ModeCommentedCode {
+		mode = config.ModeCommentedCode
+	} else if onlyPatterns != "" {
+		patterns := strings.Split(onlyPatterns, ",")
+		for i := range patterns {
+			patterns[i] = strings.TrimSpace(patterns[i])
+		}
+		err := commentConfig.SetCLIRemovePatterns(patterns)
+		if err != nil {
+			fmt.Printf("Error parsing only patterns: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := commentConfig.SetMode(mode, threshold); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	commentConfig.SetCommentKindRemoval(removeBlockComments, removeDocComments)
+
+	if noDefaultKeeps {
+		if err := commentConfig.SetNoDefaultKeeps(true); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if maxCommentRatio != 0 || maxFunctionComments != 0 {
+		err := commentConfig.SetCLIBudget(config.CommentBudget{
+			MaxAddedCommentRatio:       maxCommentRatio,
+			MaxCommentLinesPerFunction: maxFunctionComments,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		err := cli.RunConfigCommand(commentConfig, !removeDirectives, args[1:], os.Stdout)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "list" {
+		err := cli.RunListCommand(commentConfig, !removeDirectives, includeGenerated, args[1:], os.Stdout)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "stats" {
+		err := cli.RunStatsCommand(commentConfig, !removeDirectives, args[1:], os.Stdout)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "history" {
+		err := cli.RunHistoryCommand(commentConfig, !removeDirectives, includeGenerated, args[1:], os.Stdout)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "lint" {
+		findings, err := cli.RunLintCommand(commentConfig, args[1:], os.Stdout)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if findings > 0 {
+			fmt.Printf("\nFound %d comments to clean up\n", findings)
+			os.Exit(1)
+		}
+		return
+	}
+
 	inputPath := ""
 	if len(args) > 0 {
 		inputPath = args[0]
@@ -178,7 +284,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		processDirectory(currentDir, preserveDirectives, dryRun, verbose, force, commentConfig)
+		processDirectory(currentDir, preserveDirectives, dryRun, verbose, force, includeGenerated, commentConfig)
 		return
 	}
 
@@ -191,7 +297,11 @@ func main() {
 			fmt.Println("Error: can only process staged files inside a git repository")
 			os.Exit(1)
 		}
-		processStagedFiles(preserveDirectives, dryRun, verbose, commentConfig)
+		if commentConfig.Budget().Enabled() {
+			checkStagedCommentBudget(preserveDirectives, verbose, includeGenerated, commentConfig)
+			return
+		}
+		processStagedFiles(preserveDirectives, dryRun, verbose, includeGenerated, commentConfig)
 		return
 	}
 
@@ -203,7 +313,7 @@ func main() {
 		}
 
 		if !fileInfo.IsDir() {
-			processSingleFile(inputPath, preserveDirectives, dryRun, commentConfig)
+			processSingleFile(inputPath, preserveDirectives, dryRun, includeGenerated, commentConfig)
 			return
 		}
 
@@ -212,7 +322,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		processDirectory(inputPath, preserveDirectives, dryRun, verbose, force, commentConfig)
+		processDirectory(inputPath, preserveDirectives, dryRun, verbose, force, includeGenerated, commentConfig)
 		return
 	}
 
@@ -220,6 +330,11 @@ func main() {
 	fmt.Println("Usage: nocmt [path] [options]")
 	fmt.Println("       nocmt install")
 	fmt.Println("       nocmt uninstall")
+	fmt.Println("       nocmt config list|show-effective|remove|test")
+	fmt.Println("       nocmt list [-format table|csv|jsonl] [path...]")
+	fmt.Println("       nocmt stats [-format table|json] [directory...]")
+	fmt.Println("       nocmt history [-since 6.months] [-step weekly] [-authors] [-format csv|json]")
+	fmt.Println("       nocmt lint [path...]")
 	os.Exit(1)
 }
 
@@ -278,10 +393,11 @@ func getModifiedLines(filePath string) (map[int]bool, error) {
 	return modifiedLines, nil
 }
 
-func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, commentConfig *config.Config) {
+func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, includeGenerated bool, commentConfig *config.Config) {
 	factory := processor.NewProcessorFactory()
 	factory.SetPreserveDirectives(preserveDirectives)
 	factory.SetCommentConfig(commentConfig)
+	policy := walker.NewFilePolicy(".", includeGenerated)
 
 	stagedFiles, err := getStagedFiles()
 	if err != nil {
@@ -289,6 +405,8 @@ func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, comm
 		os.Exit(1)
 	}
 
+	nocmtIgnores := walker.NewNocmtIgnoreChecker(".")
+
 	if len(stagedFiles) == 0 {
 		fmt.Println("No staged files found.")
 		return
@@ -315,6 +433,20 @@ func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, comm
 			continue
 		}
 
+		ignored, err := nocmtIgnores.Ignored(filePath)
+		if err != nil {
+			fmt.Printf("Error reading %s for %s: %v\n", walker.NocmtIgnoreFile, filePath, err)
+			errors++
+			continue
+		}
+		if ignored {
+			if verbose {
+				fmt.Printf("Skipping %s: matches %s\n", filePath, walker.NocmtIgnoreFile)
+			}
+			skipped++
+			continue
+		}
+
 		proc, err := factory.GetProcessorByExtension(filePath)
 		if err != nil {
 			if verbose {
@@ -331,6 +463,20 @@ func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, comm
 			continue
 		}
 
+		reason, err := policy.SkipReason(filePath, []byte(stagedContent), true)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			errors++
+			continue
+		}
+		if reason != "" {
+			if verbose {
+				fmt.Printf("Skipping %s: %s\n", filePath, reason)
+			}
+			skipped++
+			continue
+		}
+
 		modifiedLines, err := getModifiedLines(filePath)
 		if err != nil {
 			fmt.Printf("Error getting modified lines for %s: %v\n", filePath, err)
@@ -404,11 +550,81 @@ func processStagedFiles(preserveDirectives bool, dryRun bool, verbose bool, comm
 	fmt.Printf("- Errors: %d\n", errors)
 }
 
+func checkStagedCommentBudget(preserveDirectives bool, verbose bool, includeGenerated bool, commentConfig *config.Config) {
+	factory := processor.NewProcessorFactory()
+	factory.SetPreserveDirectives(preserveDirectives)
+	factory.SetCommentConfig(commentConfig)
+	policy := walker.NewFilePolicy(".", includeGenerated)
+
+	stagedFiles, err := getStagedFiles()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	nocmtIgnores := walker.NewNocmtIgnoreChecker(".")
+
+	var files []cli.BudgetFile
+	for _, filePath := range stagedFiles {
+		if commentConfig.ShouldIgnoreFile(filePath) {
+			continue
+		}
+		ignored, err := nocmtIgnores.Ignored(filePath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if ignored {
+			continue
+		}
+		proc, err := factory.GetProcessorByExtension(filePath)
+		if err != nil {
+			if verbose {
+				fmt.Printf("Skipping %s: %v\n", filePath, err)
+			}
+			continue
+		}
+
+		stagedContent, err := getStagedFileContent(filePath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		reason, err := policy.SkipReason(filePath, []byte(stagedContent), true)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if reason != "" {
+			if verbose {
+				fmt.Printf("Skipping %s: %s\n", filePath, reason)
+			}
+			continue
+		}
+		modifiedLines, err := getModifiedLines(filePath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		added, err := processor.MeasureAddedComments(proc, stagedContent, modifiedLines, commentConfig)
+		if err != nil {
+			fmt.Printf("Warning: Skipping %s: could not measure comments: %v\n", filePath, err)
+			continue
+		}
+		files = append(files, cli.BudgetFile{Path: filePath, Added: added})
+	}
+
+	if !cli.ReportCommentBudget(commentConfig.Budget(), files, os.Stdout) {
+		os.Exit(1)
+	}
+}
+
 func processFileWithSelectiveCommentRemoval(content string, filePath string, proc processor.LanguageProcessor, modifiedLines map[int]bool, preserveDirectives bool, commentConfig *config.Config) (string, error) {
 	return processor.SelectivelyStripComments(content, filePath, proc, modifiedLines, preserveDirectives, commentConfig)
 }
 
-func processSingleFile(inputFile string, preserveDirectives bool, dryRun bool, commentConfig *config.Config) {
+func processSingleFile(inputFile string, preserveDirectives bool, dryRun bool, includeGenerated bool, commentConfig *config.Config) {
 	factory := processor.NewProcessorFactory()
 	factory.SetPreserveDirectives(preserveDirectives)
 	factory.SetCommentConfig(commentConfig)
@@ -418,6 +634,16 @@ func processSingleFile(inputFile string, preserveDirectives bool, dryRun bool, c
 		return
 	}
 
+	ignored, err := walker.IsNocmtIgnored(inputFile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if ignored {
+		fmt.Printf("Skipping %s: matches %s\n", inputFile, walker.NocmtIgnoreFile)
+		return
+	}
+
 	proc, err := factory.GetProcessorByExtension(inputFile)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
@@ -430,6 +656,16 @@ func processSingleFile(inputFile string, preserveDirectives bool, dryRun bool, c
 		os.Exit(1)
 	}
 
+	reason, err := walker.NewFilePolicy(".", includeGenerated).SkipReason(inputFile, content, false)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if reason != "" {
+		fmt.Printf("Skipping %s: %s\n", inputFile, reason)
+		return
+	}
+
 	if proc.GetLanguageName() == "go" && preserveDirectives {
 		proc = processor.NewGoProcessor(true)
 		proc.SetCommentConfig(commentConfig)
@@ -457,12 +693,13 @@ func processSingleFile(inputFile string, preserveDirectives bool, dryRun bool, c
 	}
 }
 
-func processDirectory(dirPath string, preserveDirectives bool, dryRun bool, verbose bool, force bool, commentConfig *config.Config) {
+func processDirectory(dirPath string, preserveDirectives bool, dryRun bool, verbose bool, force bool, includeGenerated bool, commentConfig *config.Config) {
 	config := walker.ProcessorConfig{
 		PreserveDirectives: preserveDirectives,
 		DryRun:             dryRun,
 		Verbose:            verbose,
 		Force:              force,
+		IncludeGenerated:   includeGenerated,
 		CommentConfig:      commentConfig,
 	}
 
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+)
+
+type BudgetFile struct {
+	Path  string
+	Added processor.AddedComments
+}
+
+func ReportCommentBudget(budget config.CommentBudget, files []BudgetFile, out io.Writer) bool {
+	var addedLines, commentLines int
+	for _, file := range files {
+		addedLines += file.Added.AddedLines
+		commentLines += file.Added.AddedCommentLines
+	}
+
+	var violations []string
+	ratio := 0.0
+	if addedLines > 0 {
+		ratio = float64(commentLines) / float64(addedLines)
+	}
+	if budget.MaxAddedCommentRatio > 0 && ratio > budget.MaxAddedCommentRatio {
+		violations = append(violations, fmt.Sprintf("%d of %d added lines are comments (ratio %.2f, maxAddedCommentRatio %.2f)", commentLines, addedLines, ratio, budget.MaxAddedCommentRatio))
+		for _, file := range files {
+			if file.Added.AddedCommentLines > 0 {
+				violations = append(violations, fmt.Sprintf("  %s: %d of %d added lines", file.Path, file.Added.AddedCommentLines, file.Added.AddedLines))
+			}
+		}
+	}
+	if budget.MaxCommentLinesPerFunction > 0 {
+		for _, file := range files {
+			for _, function := range file.Added.Functions {
+				if function.CommentLines > budget.MaxCommentLinesPerFunction {
+					violations = append(violations, fmt.Sprintf("%s:%d: %s has %d added comment lines (maxCommentLinesPerFunction %d)", file.Path, function.StartLine, function.Name, function.CommentLines, budget.MaxCommentLinesPerFunction))
+				}
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		fmt.Fprintf(out, "Comment budget met: %d of %d added lines are comments\n", commentLines, addedLines)
+		return true
+	}
+
+	fmt.Fprintln(out, "Comment budget exceeded:")
+	for _, violation := range violations {
+		fmt.Fprintf(out, "  %s\n", violation)
+	}
+	fmt.Fprintln(out, "No files were changed; trim the comments and stage them again.")
+	return false
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+)
+
+const configUsage = `Usage: nocmt config <command> [options]
+
+Commands:
+  list                      Show the patterns stored in the global and local config files
+  show-effective            Show the merged configuration with the source of each entry
+  remove [-global] [-file|-only] <pattern>
+                            Remove a pattern from the config file that defines it
+  test [-lang <language>] [-trailing] [-code <line>] "<comment text>"
+                            Report which rule or pattern decides whether a comment is kept`
+
+func RunConfigCommand(cfg *config.Config, preserveDirectives bool, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing config command\n%s", configUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		return listConfig(cfg, out)
+	case "show-effective":
+		return showEffectiveConfig(cfg, out)
+	case "remove":
+		return removeConfigPattern(cfg, args[1:], out)
+	case "test":
+		return testConfigComment(cfg, preserveDirectives, args[1:], out)
+	default:
+		return fmt.Errorf("unknown config command '%s'\n%s", args[0], configUsage)
+	}
+}
+
+func listConfig(cfg *config.Config, out io.Writer) error {
+	for _, layer := range cfg.FileLayers() {
+		fmt.Fprintf(out, "%s:\n", layer.Source)
+		writeList(out, "extends", layer.Config.Extends)
+		writeList(out, "ignorePatterns", layer.Config.IgnorePatterns)
+		writeList(out, "fileIgnorePatterns", layer.Config.FileIgnorePatterns)
+		writeList(out, "removePatterns", layer.Config.RemovePatterns)
+		var rules []string
+		for _, rule := range layer.Config.Rules {
+			rules = append(rules, rule.Label())
+		}
+		writeList(out, "rules", rules)
+		var queries []string
+		for _, query := range layer.Config.Queries {
+			queries = append(queries, query.Label())
+		}
+		writeList(out, "queries", queries)
+		writeList(out, "budget", budgetLabels(layer.Config.Budget))
+		writeList(out, "docs", docsLabels(layer.Config.Docs))
+		writeList(out, "unsafeMarkers", layer.Config.UnsafeMarkers)
+		writeList(out, "keepMarkers", layer.Config.KeepMarkers)
+		if layer.Config.NoDefaultKeeps {
+			fmt.Fprintln(out, "  noDefaultKeeps: true")
+		}
+	}
+	return nil
+}
+
+func docsLabels(docs map[string]string) []string {
+	var labels []string
+	for language, policy := range docs {
+		labels = append(labels, fmt.Sprintf("%s: %s", language, policy))
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func budgetLabels(budget *config.CommentBudget) []string {
+	if budget == nil {
+		return nil
+	}
+	var labels []string
+	if budget.MaxAddedCommentRatio > 0 {
+		labels = append(labels, fmt.Sprintf("maxAddedCommentRatio: %g", budget.MaxAddedCommentRatio))
+	}
+	if budget.MaxCommentLinesPerFunction > 0 {
+		labels = append(labels, fmt.Sprintf("maxCommentLinesPerFunction: %d", budget.MaxCommentLinesPerFunction))
+	}
+	return labels
+}
+
+func writeList(out io.Writer, name string, values []string) {
+	fmt.Fprintf(out, "  %s:\n", name)
+	if len(values) == 0 {
+		fmt.Fprintln(out, "    (none)")
+		return
+	}
+	for _, value := range values {
+		fmt.Fprintf(out, "    - %s\n", value)
+	}
+}
+
+func showEffectiveConfig(cfg *config.Config, out io.Writer) error {
+	writeSources(out, "ignorePatterns", cfg.PatternSources(config.IgnorePatternsOf))
+	writeSources(out, "fileIgnorePatterns", cfg.PatternSources(config.FileIgnorePatternsOf))
+	writeSources(out, "removePatterns", cfg.PatternSources(config.RemovePatternsOf))
+	writeRules(out, cfg.RuleSources())
+	writeSources(out, "queries", cfg.QuerySources())
+	budget := cfg.Budget()
+	fmt.Fprintln(out, "budget:")
+	if labels := budgetLabels(&budget); len(labels) > 0 {
+		for _, label := range labels {
+			fmt.Fprintf(out, "  %s\n", label)
+		}
+	} else {
+		fmt.Fprintln(out, "  (none)")
+	}
+	fmt.Fprintln(out, "docs:")
+	if labels := docsLabels(cfg.Effective().Docs); len(labels) > 0 {
+		for _, label := range labels {
+			fmt.Fprintf(out, "  %s\n", label)
+		}
+	} else {
+		fmt.Fprintln(out, "  (none)")
+	}
+	writeSources(out, "unsafeMarkers", cfg.PatternSources(config.UnsafeMarkersOf))
+	fmt.Fprintln(out, "keepMarkers:")
+	if markers := cfg.KeepMarkers(); len(markers) > 0 {
+		fmt.Fprintf(out, "  %s\n", strings.Join(markers, ", "))
+	} else {
+		fmt.Fprintln(out, "  (none)")
+	}
+	return nil
+}
+
+func writeRules(out io.Writer, rules []config.RuleSource) {
+	fmt.Fprintln(out, "rules:")
+	if len(rules) == 0 {
+		fmt.Fprintln(out, "  (none)")
+		return
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(out, "  %-40s  [%s]\n", rule.Rule.Label(), rule.Source)
+	}
+}
+
+func writeSources(out io.Writer, name string, sources []config.PatternSource) {
+	fmt.Fprintf(out, "%s:\n", name)
+	if len(sources) == 0 {
+		fmt.Fprintln(out, "  (none)")
+		return
+	}
+	writeMatches(out, sources)
+}
+
+func removeConfigPattern(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("config remove", flag.ContinueOnError)
+	flags.SetOutput(out)
+	global := flags.Bool("global", false, "Remove the pattern from the global config")
+	file := flags.Bool("file", false, "Remove a file ignore pattern instead of a comment pattern")
+	only := flags.Bool("only", false, "Remove a remove pattern instead of a comment pattern")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected exactly one pattern to remove\n%s", configUsage)
+	}
+
+	pattern := flags.Arg(0)
+	list := config.IgnorePatternsOf
+	if *file {
+		list = config.FileIgnorePatternsOf
+	}
+	if *only {
+		list = config.RemovePatternsOf
+	}
+
+	source, err := cfg.RemovePatternFrom(list, pattern, *global)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Pattern '%s' removed from %s\n", pattern, source)
+	return nil
+}
+
+func testConfigComment(cfg *config.Config, preserveDirectives bool, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("config test", flag.ContinueOnError)
+	flags.SetOutput(out)
+	language := flags.String("lang", "", "Language used to evaluate language-specific rules")
+	trailing := flags.Bool("trailing", false, "Evaluate the comment as a trailing comment after code")
+	code := flags.String("code", "", "Code the comment describes, used to detect restatements")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected exactly one comment text to test\n%s", configUsage)
+	}
+
+	comment := flags.Arg(0)
+	facts := config.CommentFacts{
+		Language: *language,
+		Text:     comment,
+		Trailing: *trailing,
+		Lines:    strings.Count(comment, "\n") + 1,
+		Code:     *code,
+	}
+	kept, reason := processor.ExplainComment(cfg, facts, preserveDirectives)
+	fmt.Fprintf(out, "The comment would be %s: %s\n", keptVerdict(kept), reason)
+
+	switch mode, _ := cfg.Mode(); {
+	case mode == config.ModeAIOnly && strings.HasPrefix(reason, "AI narration score"):
+		for _, signal := range processor.NarrationSignals(facts) {
+			fmt.Fprintf(out, "  %-40s  +%.2f\n", signal.Name, signal.Weight)
+		}
+	case mode == config.ModeCommentedCode && reason == "not commented-out code":
+		fmt.Fprintln(out, "Commented-out code is detected from the surrounding file; use 'nocmt lint -check commented-code <file>'")
+	}
+	return nil
+}
+
+func keptVerdict(kept bool) string {
+	if kept {
+		return "kept"
+	}
+	return "removed"
+}
+
+func writeMatches(out io.Writer, matches []config.PatternSource) {
+	for _, match := range matches {
+		fmt.Fprintf(out, "  %-40s  [%s]\n", match.Pattern, match.Source)
+	}
+}
@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"os/exec"
+	"strconv"
+	"strings"
 )
 
 func IsGitRepo() bool {
@@ -9,3 +15,67 @@ func IsGitRepo() bool {
 	err := cmd.Run()
 	return err == nil
 }
+
+func gitOutput(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+type blobReader struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func newBlobReader() (*blobReader, error) {
+	cmd := exec.Command("git", "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start git cat-file: %w", err)
+	}
+	return &blobReader{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+func (r *blobReader) Read(object string) (string, bool, error) {
+	if _, err := fmt.Fprintln(r.stdin, object); err != nil {
+		return "", false, fmt.Errorf("failed to request %s: %w", object, err)
+	}
+	header, err := r.stdout.ReadString('\n')
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) == 2 && fields[1] == "missing" {
+		return "", false, nil
+	}
+	if len(fields) != 3 {
+		return "", false, fmt.Errorf("unexpected git cat-file output for %s: %q", object, header)
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", false, fmt.Errorf("unexpected git cat-file size for %s: %w", object, err)
+	}
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(r.stdout, content); err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	return string(content[:size]), true, nil
+}
+
+func (r *blobReader) Close() error {
+	r.stdin.Close()
+	return r.cmd.Wait()
+}
@@ -0,0 +1,412 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+	"nocmt/internal/walker"
+)
+
+const historyUsage = `Usage: nocmt history [-since 6.months] [-step daily|weekly|monthly] [-authors] [-format csv|json]
+
+Samples the last commit of each step on the current branch and reports comment density per language, reading files from git without a checkout.`
+
+type historyPoint struct {
+	Date       string           `json:"date"`
+	Commit     string           `json:"commit"`
+	Languages  []statsGroup     `json:"languages"`
+	Total      statsGroup       `json:"total"`
+	Skipped    int              `json:"skipped"`
+	Unparsable int              `json:"unparsable"`
+	Authors    []authorComments `json:"authors,omitempty"`
+}
+
+type authorComments struct {
+	Name              string `json:"name"`
+	Commits           int    `json:"commits"`
+	AddedCommentLines int    `json:"addedCommentLines"`
+}
+
+type historyReport struct {
+	Since  string         `json:"since"`
+	Step   string         `json:"step"`
+	Points []historyPoint `json:"points"`
+}
+
+type gitCommit struct {
+	Hash   string
+	Time   time.Time
+	Author string
+}
+
+type historySampler struct {
+	cfg          *config.Config
+	factory      *processor.ProcessorFactory
+	blobs        *blobReader
+	root         string
+	policy       *walker.FilePolicy
+	nocmtIgnores *walker.HierarchicalGitIgnoreChecker
+	cache        map[string]blobResult
+}
+
+type blobOutcome int
+
+const (
+	blobCounted blobOutcome = iota
+	blobUnsupported
+	blobSkipped
+	blobUnparsable
+)
+
+type blobResult struct {
+	stats   *walker.FileStats
+	outcome blobOutcome
+}
+
+type treeSample struct {
+	files      []walker.FileStats
+	skipped    int
+	unparsable int
+}
+
+func RunHistoryCommand(cfg *config.Config, preserveDirectives, includeGenerated bool, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { fmt.Fprintln(out, historyUsage) }
+	since := flags.String("since", "6.months", "How far back to sample, in any date format git accepts")
+	step := flags.String("step", "weekly", "Sampling interval: daily, weekly or monthly")
+	authors := flags.Bool("authors", false, "Attribute comment lines added in each step to commit authors")
+	format := flags.String("format", "csv", "Output format: csv or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if _, err := stepStart(time.Time{}, *step); err != nil {
+		return fmt.Errorf("%w\n%s", err, historyUsage)
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format '%s'\n%s", *format, historyUsage)
+	}
+	if !IsGitRepo() {
+		return fmt.Errorf("history can only be read inside a git repository")
+	}
+
+	commits, err := gitCommits("--first-parent", "--since="+*since)
+	if err != nil {
+		return err
+	}
+
+	topLevel, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	root := strings.TrimSpace(string(topLevel))
+
+	blobs, err := newBlobReader()
+	if err != nil {
+		return err
+	}
+	defer blobs.Close()
+
+	factory := processor.NewProcessorFactory()
+	factory.SetPreserveDirectives(preserveDirectives)
+	factory.SetCommentConfig(cfg)
+	sampler := &historySampler{
+		cfg:          cfg,
+		factory:      factory,
+		blobs:        blobs,
+		root:         root,
+		policy:       walker.NewFilePolicy(root, includeGenerated),
+		nocmtIgnores: walker.NewNocmtIgnoreChecker(root),
+		cache:        make(map[string]blobResult),
+	}
+
+	report := historyReport{Since: *since, Step: *step, Points: []historyPoint{}}
+	for _, commit := range sampleCommits(commits, *step) {
+		sample, err := sampler.treeStats(commit.Hash)
+		if err != nil {
+			return err
+		}
+		date, _ := stepStart(commit.Time, *step)
+		report.Points = append(report.Points, historyPoint{
+			Date:       date.Format(time.DateOnly),
+			Commit:     commit.Hash,
+			Languages:  groupStats(sample.files, func(stats walker.FileStats) string { return stats.Language }),
+			Total:      totalStats(sample.files),
+			Skipped:    sample.skipped,
+			Unparsable: sample.unparsable,
+		})
+	}
+
+	if *authors {
+		if err := sampler.attributeAuthors(report.Points, *since, *step); err != nil {
+			return err
+		}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeHistoryCSV(out, report)
+}
+
+func stepStart(t time.Time, step string) (time.Time, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch step {
+	case "daily":
+		return day, nil
+	case "weekly":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case "monthly":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown step '%s'", step)
+	}
+}
+
+func gitCommits(args ...string) ([]gitCommit, error) {
+	args = append([]string{"log", "--reverse", "--format=%H%x00%ct%x00%an"}, args...)
+	out, err := gitOutput(append(args, "HEAD")...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []gitCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected commit time %q: %w", fields[1], err)
+		}
+		commits = append(commits, gitCommit{Hash: fields[0], Time: time.Unix(seconds, 0).UTC(), Author: fields[2]})
+	}
+	return commits, nil
+}
+
+func sampleCommits(commits []gitCommit, step string) []gitCommit {
+	var samples []gitCommit
+	var last time.Time
+	for _, commit := range commits {
+		start, _ := stepStart(commit.Time, step)
+		if len(samples) > 0 && !start.After(last) {
+			samples[len(samples)-1] = commit
+			continue
+		}
+		samples = append(samples, commit)
+		last = start
+	}
+	return samples
+}
+
+func (s *historySampler) treeStats(commit string) (treeSample, error) {
+	out, err := gitOutput("ls-tree", "-r", "-z", "--full-tree", commit)
+	if err != nil {
+		return treeSample{}, err
+	}
+
+	var sample treeSample
+	for _, entry := range strings.Split(string(out), "\x00") {
+		meta, path, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		result, err := s.blobStats(fields[2], path)
+		if err != nil {
+			return treeSample{}, err
+		}
+		switch result.outcome {
+		case blobCounted:
+			sample.files = append(sample.files, *result.stats)
+		case blobSkipped:
+			sample.skipped++
+		case blobUnparsable:
+			sample.unparsable++
+		}
+	}
+	return sample, nil
+}
+
+func (s *historySampler) blobStats(object, path string) (blobResult, error) {
+	if s.cfg != nil && s.cfg.ShouldIgnoreFile(path) {
+		return blobResult{outcome: blobSkipped}, nil
+	}
+	proc, err := s.factory.GetProcessorByExtension(filepath.Base(path))
+	if err != nil {
+		return blobResult{outcome: blobUnsupported}, nil
+	}
+	absPath := filepath.Join(s.root, filepath.FromSlash(path))
+	ignored, err := s.nocmtIgnores.Ignored(absPath)
+	if err != nil {
+		return blobResult{}, err
+	}
+	if ignored {
+		return blobResult{outcome: blobSkipped}, nil
+	}
+
+	key := object + "\x00" + path
+	if result, ok := s.cache[key]; ok {
+		return result, nil
+	}
+
+	content, found, err := s.blobs.Read(object)
+	if err != nil || !found {
+		return blobResult{outcome: blobUnsupported}, err
+	}
+	reason, err := s.policy.SkipReason(absPath, []byte(content), false)
+	if err != nil {
+		return blobResult{}, err
+	}
+
+	result := blobResult{outcome: blobSkipped}
+	if reason == "" {
+		result = s.measure(proc, path, content)
+	}
+	s.cache[key] = result
+	return result, nil
+}
+
+func (s *historySampler) measure(proc processor.LanguageProcessor, path, content string) blobResult {
+	stripped, err := proc.StripComments(content)
+	if err != nil {
+		return blobResult{outcome: blobUnparsable}
+	}
+	stats, err := walker.CollectFileStats(proc, path, content, stripped, s.cfg)
+	if err != nil {
+		return blobResult{outcome: blobUnparsable}
+	}
+	return blobResult{stats: &stats, outcome: blobCounted}
+}
+
+func (s *historySampler) attributeAuthors(points []historyPoint, since, step string) error {
+	if len(points) == 0 {
+		return nil
+	}
+	commits, err := gitCommits("--no-merges", "--since="+since)
+	if err != nil {
+		return err
+	}
+
+	byPoint := make(map[int]map[string]*authorComments)
+	for _, commit := range commits {
+		start, _ := stepStart(commit.Time, step)
+		index := sort.Search(len(points), func(i int) bool {
+			return points[i].Date >= start.Format(time.DateOnly)
+		})
+		if index == len(points) {
+			continue
+		}
+
+		added, err := s.addedCommentLines(commit.Hash)
+		if err != nil {
+			return err
+		}
+		if byPoint[index] == nil {
+			byPoint[index] = make(map[string]*authorComments)
+		}
+		author, ok := byPoint[index][commit.Author]
+		if !ok {
+			author = &authorComments{Name: commit.Author}
+			byPoint[index][commit.Author] = author
+		}
+		author.Commits++
+		author.AddedCommentLines += added
+	}
+
+	for index, authors := range byPoint {
+		for _, author := range authors {
+			points[index].Authors = append(points[index].Authors, *author)
+		}
+		sort.Slice(points[index].Authors, func(i, j int) bool {
+			a, b := points[index].Authors[i], points[index].Authors[j]
+			if a.AddedCommentLines != b.AddedCommentLines {
+				return a.AddedCommentLines > b.AddedCommentLines
+			}
+			return a.Name < b.Name
+		})
+	}
+	return nil
+}
+
+func (s *historySampler) addedCommentLines(commit string) (int, error) {
+	out, err := gitOutput("diff-tree", "-r", "-z", "--root", "--no-commit-id", "--diff-filter=AM", commit)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	entries := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i+1 < len(entries); i += 2 {
+		fields := strings.Fields(entries[i])
+		if len(fields) != 5 {
+			continue
+		}
+		path := entries[i+1]
+		after, err := s.blobStats(fields[3], path)
+		if err != nil {
+			return added, err
+		}
+		if after.outcome != blobCounted {
+			continue
+		}
+		before := 0
+		if strings.Trim(fields[2], "0") != "" {
+			previous, err := s.blobStats(fields[2], path)
+			if err != nil {
+				return added, err
+			}
+			if previous.outcome == blobCounted {
+				before = previous.stats.CommentLines
+			}
+		}
+		added += max(after.stats.CommentLines-before, 0)
+	}
+	return added, nil
+}
+
+func writeHistoryCSV(out io.Writer, report historyReport) error {
+	writer := csv.NewWriter(out)
+	writer.Write([]string{"date", "commit", "series", "name", "files", "code_lines", "comment_lines", "removable_lines", "density", "commits", "added_comment_lines", "skipped", "unparsable"})
+	for _, point := range report.Points {
+		writeGroup := func(series string, group statsGroup, skipped, unparsable string) {
+			writer.Write([]string{
+				point.Date,
+				point.Commit,
+				series,
+				group.Name,
+				strconv.Itoa(group.Files),
+				strconv.Itoa(group.CodeLines),
+				strconv.Itoa(group.CommentLines),
+				strconv.Itoa(group.RemovableLines),
+				strconv.FormatFloat(group.Density, 'f', 4, 64),
+				"",
+				"",
+				skipped,
+				unparsable,
+			})
+		}
+		for _, group := range point.Languages {
+			writeGroup("language", group, "", "")
+		}
+		writeGroup("total", point.Total, strconv.Itoa(point.Skipped), strconv.Itoa(point.Unparsable))
+		for _, author := range point.Authors {
+			writer.Write([]string{point.Date, point.Commit, "author", author.Name, "", "", "", "", "", strconv.Itoa(author.Commits), strconv.Itoa(author.AddedCommentLines), "", ""})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+)
+
+const (
+	lintCheckRedundant     = "redundant"
+	lintCheckCommentedCode = "commented-code"
+)
+
+const lintUsage = `Usage: nocmt lint [-check redundant,commented-code] [-threshold <0-1>] [path...]
+
+Reports redundant comments and commented-out code without changing any file.`
+
+func RunLintCommand(cfg *config.Config, args []string, out io.Writer) (int, error) {
+	_, defaultThreshold := cfg.Mode()
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { fmt.Fprintln(out, lintUsage) }
+	checkList := flags.String("check", lintCheckRedundant+","+lintCheckCommentedCode, "Comma-separated list of checks to run")
+	threshold := flags.Float64("threshold", defaultThreshold, "Share of comment words covered by the code for a comment to be reported as redundant")
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+
+	checks := make(map[string]bool)
+	for _, check := range strings.Split(*checkList, ",") {
+		check = strings.TrimSpace(check)
+		if check != lintCheckRedundant && check != lintCheckCommentedCode {
+			return 0, fmt.Errorf("unknown lint check '%s'\n%s", check, lintUsage)
+		}
+		checks[check] = true
+	}
+
+	factory := processor.NewProcessorFactory()
+	factory.SetPreserveDirectives(true)
+	factory.SetCommentConfig(cfg)
+
+	findings := 0
+	lintFile := func(path string) error {
+		if cfg.ShouldIgnoreFile(path) {
+			return nil
+		}
+		proc, err := factory.GetProcessorByExtension(filepath.Base(path))
+		if err != nil {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if linter, ok := proc.(processor.RedundancyLinter); ok && checks[lintCheckRedundant] {
+			redundant, err := linter.RedundantComments(string(content), *threshold)
+			if err != nil {
+				fmt.Fprintf(out, "%s: skipped: %v\n", path, err)
+				return nil
+			}
+			for _, comment := range redundant {
+				fmt.Fprintf(out, "%s:%d: redundant comment restates %q (%.0f%% covered): %s\n", path, comment.Line, comment.Code, comment.Coverage*100, comment.Text)
+			}
+			findings += len(redundant)
+		}
+
+		if finder, ok := proc.(processor.CommentedCodeFinder); ok && checks[lintCheckCommentedCode] {
+			blocks, err := finder.CommentedCodeBlocks(string(content))
+			if err != nil {
+				fmt.Fprintf(out, "%s: skipped: %v\n", path, err)
+				return nil
+			}
+			for _, block := range blocks {
+				firstLine, _, _ := strings.Cut(block.Code, "\n")
+				fmt.Fprintf(out, "%s:%d-%d: commented-out code: %s\n", path, block.StartLine, block.EndLine, firstLine)
+			}
+			findings += len(blocks)
+		}
+		return nil
+	}
+
+	err := forEachFile(flags.Args(), lintFile)
+	return findings, err
+}
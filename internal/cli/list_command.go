@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+	"nocmt/internal/walker"
+)
+
+const listUsage = `Usage: nocmt list [-format table|csv|jsonl] [path...]
+
+Lists every comment with whether nocmt would remove it and why, without changing any file.`
+
+type listedComment struct {
+	File    string `json:"file"`
+	Skipped bool   `json:"skipped,omitempty"`
+	processor.CommentInfo
+}
+
+func RunListCommand(cfg *config.Config, preserveDirectives, includeGenerated bool, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { fmt.Fprintln(out, listUsage) }
+	format := flags.String("format", "table", "Output format: table, csv or jsonl")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var write func(comment listedComment) error
+	var flush func() error
+	switch *format {
+	case "table":
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "FILE\tLINES\tLANGUAGE\tKIND\tACTION\tREASON\tTEXT")
+		write = func(comment listedComment) error {
+			text, _, _ := strings.Cut(comment.Text, "\n")
+			_, err := fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", comment.File, lineRange(comment.CommentInfo), comment.Language, comment.Kind, action(comment), comment.Reason, text)
+			return err
+		}
+		flush = table.Flush
+	case "csv":
+		writer := csv.NewWriter(out)
+		if err := writer.Write([]string{"file", "start_line", "end_line", "language", "kind", "remove", "reason", "text"}); err != nil {
+			return err
+		}
+		write = func(comment listedComment) error {
+			return writer.Write([]string{
+				comment.File,
+				strconv.Itoa(comment.StartLine),
+				strconv.Itoa(comment.EndLine),
+				comment.Language,
+				comment.Kind,
+				strconv.FormatBool(comment.Remove),
+				comment.Reason,
+				comment.Text,
+			})
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case "jsonl":
+		encoder := json.NewEncoder(out)
+		write = func(comment listedComment) error {
+			return encoder.Encode(comment)
+		}
+		flush = func() error { return nil }
+	default:
+		return fmt.Errorf("unknown format '%s'\n%s", *format, listUsage)
+	}
+
+	factory := processor.NewProcessorFactory()
+	factory.SetPreserveDirectives(preserveDirectives)
+	factory.SetCommentConfig(cfg)
+	policy := walker.NewFilePolicy(".", includeGenerated)
+
+	err := forEachFile(flags.Args(), func(path string) error {
+		if cfg.ShouldIgnoreFile(path) {
+			return nil
+		}
+		proc, err := factory.GetProcessorByExtension(filepath.Base(path))
+		if err != nil {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		reason, err := policy.SkipReason(path, content, false)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			return write(listedComment{
+				File:        path,
+				Skipped:     true,
+				CommentInfo: processor.CommentInfo{Language: proc.GetLanguageName(), Kind: "file", Reason: reason},
+			})
+		}
+		comments, err := processor.InspectComments(proc, string(content), cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", path, err)
+			return nil
+		}
+		for _, comment := range comments {
+			if err := write(listedComment{File: path, CommentInfo: comment}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+func lineRange(comment processor.CommentInfo) string {
+	if comment.StartLine == 0 {
+		return "-"
+	}
+	if comment.StartLine == comment.EndLine {
+		return strconv.Itoa(comment.StartLine)
+	}
+	return fmt.Sprintf("%d-%d", comment.StartLine, comment.EndLine)
+}
+
+func action(comment listedComment) string {
+	switch {
+	case comment.Skipped:
+		return "skip"
+	case comment.Remove:
+		return "remove"
+	}
+	return "keep"
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"nocmt/internal/walker"
+)
+
+func forEachFile(paths []string, visit walker.FileProcessor) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			err = visit(path)
+		} else {
+			err = (&walker.Walker{}).Walk(path, visit)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
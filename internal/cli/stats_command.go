@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"nocmt/internal/config"
+	"nocmt/internal/walker"
+)
+
+const statsUsage = `Usage: nocmt stats [-format table|json] [directory...]
+
+Reports comment density and the bytes and estimated tokens that removing comments would save, without changing any file.`
+
+type statsGroup struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+	walker.FileStats
+	Density float64 `json:"density"`
+}
+
+type statsReport struct {
+	Languages   []statsGroup `json:"languages"`
+	Directories []statsGroup `json:"directories"`
+	Total       statsGroup   `json:"total"`
+	Unparsable  int          `json:"unparsable"`
+}
+
+func RunStatsCommand(cfg *config.Config, preserveDirectives bool, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() { fmt.Fprintln(out, statsUsage) }
+	format := flags.String("format", "table", "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format '%s'\n%s", *format, statsUsage)
+	}
+
+	roots := flags.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	var files []walker.FileStats
+	unparsable := 0
+	for _, root := range roots {
+		integration := walker.NewProcessorIntegration(walker.ProcessorConfig{
+			PreserveDirectives: preserveDirectives,
+			DryRun:             true,
+			CollectStats:       true,
+			CommentConfig:      cfg,
+		})
+		if err := integration.ProcessRepository(root); err != nil {
+			return err
+		}
+		_, _, errors := integration.GetStats()
+		unparsable += errors
+		for _, stats := range integration.FileStats() {
+			if relative, err := filepath.Rel(root, stats.Path); err == nil {
+				stats.Path = relative
+			}
+			files = append(files, stats)
+		}
+	}
+
+	report := statsReport{
+		Languages:   groupStats(files, func(stats walker.FileStats) string { return stats.Language }),
+		Directories: groupStats(files, func(stats walker.FileStats) string { return filepath.Dir(stats.Path) }),
+		Total:       totalStats(files),
+		Unparsable:  unparsable,
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	writeStatsTable(out, "LANGUAGE", report.Languages, report.Total)
+	fmt.Fprintln(out)
+	writeStatsTable(out, "DIRECTORY", report.Directories, report.Total)
+	if report.Unparsable > 0 {
+		fmt.Fprintf(out, "\n%d file(s) could not be parsed and are not counted\n", report.Unparsable)
+	}
+	return nil
+}
+
+func groupStats(files []walker.FileStats, key func(walker.FileStats) string) []statsGroup {
+	groups := make(map[string]*statsGroup)
+	for _, stats := range files {
+		name := key(stats)
+		group, ok := groups[name]
+		if !ok {
+			group = &statsGroup{Name: name}
+			groups[name] = group
+		}
+		group.Files++
+		group.Add(stats)
+	}
+
+	result := []statsGroup{}
+	for _, group := range groups {
+		group.Density = group.FileStats.Density()
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TokensSaved != result[j].TokensSaved {
+			return result[i].TokensSaved > result[j].TokensSaved
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func totalStats(files []walker.FileStats) statsGroup {
+	total := statsGroup{Name: "total", Files: len(files)}
+	for _, stats := range files {
+		total.Add(stats)
+	}
+	total.Density = total.FileStats.Density()
+	return total
+}
+
+func writeStatsTable(out io.Writer, title string, groups []statsGroup, total statsGroup) {
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(table, "%s\tFILES\tCODE\tCOMMENTS\tREMOVABLE\tDENSITY\tBYTES SAVED\tTOKENS SAVED\t\n", title)
+	for _, group := range append(groups, total) {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%.1f%%\t%d\t%d\t\n", group.Name, group.Files, group.CodeLines, group.CommentLines, group.RemovableLines, group.Density*100, group.BytesSaved, group.TokensSaved)
+	}
+	table.Flush()
+}
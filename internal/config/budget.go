@@ -0,0 +1,48 @@
+package config
+
+import "fmt"
+
+type CommentBudget struct {
+	MaxAddedCommentRatio       float64 `json:"maxAddedCommentRatio,omitempty"`
+	MaxCommentLinesPerFunction int     `json:"maxCommentLinesPerFunction,omitempty"`
+}
+
+func (b CommentBudget) Enabled() bool {
+	return b.MaxAddedCommentRatio > 0 || b.MaxCommentLinesPerFunction > 0
+}
+
+func (b CommentBudget) validate() error {
+	if b.MaxAddedCommentRatio < 0 || b.MaxAddedCommentRatio > 1 {
+		return fmt.Errorf("budget maxAddedCommentRatio must be between 0 and 1, got %g", b.MaxAddedCommentRatio)
+	}
+	if b.MaxCommentLinesPerFunction < 0 {
+		return fmt.Errorf("budget maxCommentLinesPerFunction must not be negative, got %d", b.MaxCommentLinesPerFunction)
+	}
+	return nil
+}
+
+func mergeBudgets(base, overlay *CommentBudget) *CommentBudget {
+	if overlay == nil {
+		return base
+	}
+	merged := CommentBudget{}
+	if base != nil {
+		merged = *base
+	}
+	if overlay.MaxAddedCommentRatio != 0 {
+		merged.MaxAddedCommentRatio = overlay.MaxAddedCommentRatio
+	}
+	if overlay.MaxCommentLinesPerFunction != 0 {
+		merged.MaxCommentLinesPerFunction = overlay.MaxCommentLinesPerFunction
+	}
+	return &merged
+}
+
+func (c *Config) SetCLIBudget(budget CommentBudget) error {
+	c.cliBudget = budget
+	return c.compilePatterns()
+}
+
+func (c *Config) Budget() CommentBudget {
+	return c.budget
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,35 +10,138 @@ import (
 	"strings"
 )
 
+const localConfigFile = ".nocmt.json"
+
+const (
+	ModeAll           = "all"
+	ModeAIOnly        = "ai-only"
+	ModeRedundant     = "redundant"
+	ModeCommentedCode = "commented-code"
+)
+
 type CommentConfig struct {
-	IgnorePatterns     []string `json:"ignorePatterns"`
-	FileIgnorePatterns []string `json:"fileIgnorePatterns"`
+	Extends            []string          `json:"extends,omitempty"`
+	IgnorePatterns     []string          `json:"ignorePatterns"`
+	FileIgnorePatterns []string          `json:"fileIgnorePatterns"`
+	RemovePatterns     []string          `json:"removePatterns,omitempty"`
+	Rules              []Rule            `json:"rules,omitempty"`
+	Queries            []QueryRule       `json:"queries,omitempty"`
+	Budget             *CommentBudget    `json:"budget,omitempty"`
+	Docs               map[string]string `json:"docs,omitempty"`
+	UnsafeMarkers      []string          `json:"unsafeMarkers,omitempty"`
+	KeepMarkers        []string          `json:"keepMarkers,omitempty"`
+	NoDefaultKeeps     bool              `json:"noDefaultKeeps,omitempty"`
+}
+
+type Layer struct {
+	Source string
+	Config CommentConfig
 }
 
 type Config struct {
-	Global               CommentConfig
-	Local                CommentConfig
-	CLIPatterns          []string
-	CLIFilePatterns      []string
-	compiledPatterns     []*regexp.Regexp
-	compiledFilePatterns []*regexp.Regexp
+	Global                 CommentConfig
+	Local                  CommentConfig
+	CLIPatterns            []string
+	CLIFilePatterns        []string
+	CLIRemovePatterns      []string
+	globalPath             string
+	globalExtends          []Layer
+	localExtends           []Layer
+	compiledPatterns       []*regexp.Regexp
+	compiledFilePatterns   []*regexp.Regexp
+	compiledRemovePatterns []*regexp.Regexp
+	compiledRules          []compiledRule
+	mode                   string
+	threshold              float64
+	cliBudget              CommentBudget
+	budget                 CommentBudget
+	docs                   map[string]string
+	unsafeMarkers          []string
+	cliNoDefaultKeeps      bool
+	keepMarkers            *regexp.Regexp
+	removeBlockComments    bool
+	removeDocComments      bool
 }
 
 func New() *Config {
-	return &Config{}
+	c := &Config{}
+	c.keepMarkers, _ = compileKeepMarkers(DefaultKeepMarkers)
+	return c
 }
 
 func (c *Config) LoadConfigurations() error {
+	var errs []error
+
 	homeDir, err := os.UserHomeDir()
 	if err == nil {
-		globalConfigPath := filepath.Join(homeDir, ".nocmt", "config.json")
-		c.Global, _ = loadConfigFile(globalConfigPath)
+		c.globalPath = filepath.Join(homeDir, ".nocmt", "config.json")
+		c.Global, _ = loadConfigFile(c.globalPath)
+		if err := resolveQueryFiles(&c.Global, filepath.Dir(c.globalPath)); err != nil {
+			errs = append(errs, err)
+		}
+		c.globalExtends, err = resolveExtends(c.Global, filepath.Dir(c.globalPath), []string{c.globalPath})
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	localConfigPath := ".nocmt.json"
-	c.Local, _ = loadConfigFile(localConfigPath)
+	c.Local, _ = loadConfigFile(localConfigFile)
+	localPath, err := filepath.Abs(localConfigFile)
+	if err != nil {
+		localPath = localConfigFile
+	}
+	if err := resolveQueryFiles(&c.Local, filepath.Dir(localPath)); err != nil {
+		errs = append(errs, err)
+	}
+	c.localExtends, err = resolveExtends(c.Local, filepath.Dir(localPath), []string{localPath})
+	if err != nil {
+		errs = append(errs, err)
+	}
 
-	return c.compilePatterns()
+	if err := c.compilePatterns(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) globalSource() string {
+	if c.globalPath == "" {
+		return "global"
+	}
+	return c.globalPath
+}
+
+func (c *Config) FileLayers() []Layer {
+	return []Layer{
+		{Source: c.globalSource(), Config: c.Global},
+		{Source: localConfigFile, Config: c.Local},
+	}
+}
+
+func (c *Config) Layers() []Layer {
+	layers := append([]Layer{}, c.globalExtends...)
+	layers = append(layers, Layer{Source: c.globalSource(), Config: c.Global})
+	layers = append(layers, c.localExtends...)
+	layers = append(layers, Layer{Source: localConfigFile, Config: c.Local})
+	cli := CommentConfig{
+		IgnorePatterns:     c.CLIPatterns,
+		FileIgnorePatterns: c.CLIFilePatterns,
+		RemovePatterns:     c.CLIRemovePatterns,
+		NoDefaultKeeps:     c.cliNoDefaultKeeps,
+	}
+	if c.cliBudget.Enabled() {
+		cli.Budget = &c.cliBudget
+	}
+	layers = append(layers, Layer{Source: "cli", Config: cli})
+	return layers
+}
+
+func (c *Config) Effective() CommentConfig {
+	var effective CommentConfig
+	for _, layer := range c.Layers() {
+		effective = mergeConfigs(effective, layer.Config)
+	}
+	return effective
 }
 
 func (c *Config) SetCLIPatterns(patterns []string) error {
@@ -50,6 +154,61 @@ func (c *Config) SetCLIFilePatterns(patterns []string) error {
 	return c.compilePatterns()
 }
 
+func (c *Config) SetCLIRemovePatterns(patterns []string) error {
+	c.CLIRemovePatterns = patterns
+	return c.compilePatterns()
+}
+
+func (c *Config) SetMode(mode string, threshold float64) error {
+	switch mode {
+	case "", ModeAll, ModeAIOnly, ModeRedundant, ModeCommentedCode:
+	default:
+		return fmt.Errorf("unknown mode '%s' (available: %s, %s, %s, %s)", mode, ModeAll, ModeAIOnly, ModeRedundant, ModeCommentedCode)
+	}
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %g", threshold)
+	}
+	c.mode = mode
+	c.threshold = threshold
+	return nil
+}
+
+func (c *Config) Mode() (mode string, threshold float64) {
+	if c.mode == "" {
+		return ModeAll, c.threshold
+	}
+	return c.mode, c.threshold
+}
+
+func (c *Config) SetCommentKindRemoval(blockComments, docComments bool) {
+	c.removeBlockComments = blockComments
+	c.removeDocComments = docComments
+}
+
+func (c *Config) RemovesBlockComments() bool {
+	return c != nil && c.removeBlockComments
+}
+
+func (c *Config) RemovesDocComments() bool {
+	return c != nil && c.removeDocComments
+}
+
+func (c *Config) HasRemovePatterns() bool {
+	return len(c.compiledRemovePatterns) > 0
+}
+
+func (c *Config) IsRemovalCandidate(comment string) bool {
+	if !c.HasRemovePatterns() {
+		return true
+	}
+	for _, pattern := range c.compiledRemovePatterns {
+		if pattern.MatchString(comment) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) ShouldIgnoreComment(comment string) bool {
 	for _, pattern := range c.compiledPatterns {
 		if pattern.MatchString(comment) {
@@ -102,12 +261,16 @@ func (c *Config) ShouldIgnoreFile(filename string) bool {
 func (c *Config) compilePatterns() error {
 	c.compiledPatterns = nil
 	c.compiledFilePatterns = nil
+	c.compiledRemovePatterns = nil
+	c.compiledRules = nil
+	c.budget = CommentBudget{}
+	c.docs = nil
+	c.unsafeMarkers = nil
+	c.keepMarkers = nil
 
-	allPatterns := append([]string{}, c.Global.IgnorePatterns...)
-	allPatterns = append(allPatterns, c.Local.IgnorePatterns...)
-	allPatterns = append(allPatterns, c.CLIPatterns...)
+	effective := c.Effective()
 
-	for _, pattern := range allPatterns {
+	for _, pattern := range effective.IgnorePatterns {
 		compiled, err := regexp.Compile(pattern)
 		if err != nil {
 			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
@@ -115,11 +278,7 @@ func (c *Config) compilePatterns() error {
 		c.compiledPatterns = append(c.compiledPatterns, compiled)
 	}
 
-	allFilePatterns := append([]string{}, c.Global.FileIgnorePatterns...)
-	allFilePatterns = append(allFilePatterns, c.Local.FileIgnorePatterns...)
-	allFilePatterns = append(allFilePatterns, c.CLIFilePatterns...)
-
-	for _, pattern := range allFilePatterns {
+	for _, pattern := range effective.FileIgnorePatterns {
 		compiled, err := regexp.Compile(pattern)
 		if err != nil {
 			return fmt.Errorf("invalid file pattern '%s': %w", pattern, err)
@@ -127,6 +286,51 @@ func (c *Config) compilePatterns() error {
 		c.compiledFilePatterns = append(c.compiledFilePatterns, compiled)
 	}
 
+	for _, pattern := range effective.RemovePatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid remove pattern '%s': %w", pattern, err)
+		}
+		c.compiledRemovePatterns = append(c.compiledRemovePatterns, compiled)
+	}
+
+	for _, query := range effective.Queries {
+		if err := validateQuery(query); err != nil {
+			return err
+		}
+	}
+
+	if effective.Budget != nil {
+		if err := effective.Budget.validate(); err != nil {
+			return err
+		}
+		c.budget = *effective.Budget
+	}
+
+	if err := validateDocs(effective.Docs); err != nil {
+		return err
+	}
+	c.docs = effective.Docs
+
+	if err := validateUnsafeMarkers(effective.UnsafeMarkers); err != nil {
+		return err
+	}
+	c.unsafeMarkers = effective.UnsafeMarkers
+
+	keepMarkers, err := compileKeepMarkers(c.KeepMarkers())
+	if err != nil {
+		return err
+	}
+	c.keepMarkers = keepMarkers
+
+	for _, source := range c.RuleSources() {
+		compiled, err := compileRule(source.Rule, source.Source)
+		if err != nil {
+			return err
+		}
+		c.compiledRules = append(c.compiledRules, compiled)
+	}
+
 	return nil
 }
 
@@ -136,7 +340,7 @@ func (c *Config) AddIgnorePattern(pattern string) error {
 		return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
 	}
 
-	c.Local.IgnorePatterns = append(c.Local.IgnorePatterns, pattern)
+	c.Local.IgnorePatterns = appendUnique(c.Local.IgnorePatterns, []string{pattern})
 
 	return c.SaveLocalConfig()
 }
@@ -147,7 +351,7 @@ func (c *Config) AddFileIgnorePattern(pattern string) error {
 		return fmt.Errorf("invalid file pattern '%s': %w", pattern, err)
 	}
 
-	c.Local.FileIgnorePatterns = append(c.Local.FileIgnorePatterns, pattern)
+	c.Local.FileIgnorePatterns = appendUnique(c.Local.FileIgnorePatterns, []string{pattern})
 
 	return c.SaveLocalConfig()
 }
@@ -158,13 +362,13 @@ func (c *Config) AddGlobalFileIgnorePattern(pattern string) error {
 		return fmt.Errorf("invalid file pattern '%s': %w", pattern, err)
 	}
 
-	c.Global.FileIgnorePatterns = append(c.Global.FileIgnorePatterns, pattern)
+	c.Global.FileIgnorePatterns = appendUnique(c.Global.FileIgnorePatterns, []string{pattern})
 
 	return c.SaveGlobalConfig()
 }
 
 func (c *Config) SaveLocalConfig() error {
-	return saveConfigFile(".nocmt.json", c.Local)
+	return saveConfigFile(localConfigFile, c.Local)
 }
 
 func (c *Config) SaveGlobalConfig() error {
@@ -188,7 +392,7 @@ func (c *Config) AddGlobalIgnorePattern(pattern string) error {
 		return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
 	}
 
-	c.Global.IgnorePatterns = append(c.Global.IgnorePatterns, pattern)
+	c.Global.IgnorePatterns = appendUnique(c.Global.IgnorePatterns, []string{pattern})
 
 	return c.SaveGlobalConfig()
 }
@@ -204,11 +408,14 @@ func loadConfigFile(path string) (CommentConfig, error) {
 		return config, err
 	}
 
-	err = json.Unmarshal(data, &config)
-	if err != nil {
-		return config, fmt.Errorf("error parsing config file %s: %w", path, err)
-	}
+	return parseConfig(data, path)
+}
 
+func parseConfig(data []byte, source string) (CommentConfig, error) {
+	config := CommentConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error parsing config file %s: %w", source, err)
+	}
 	return config, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -809,3 +810,569 @@ func TestPreCommitHookIgnorePatterns(t *testing.T) {
 		t.Errorf("Files to process mismatch.\nGot: %v\nWant: %v", filesToProcess, expectedToProcess)
 	}
 }
+
+func chdirWithHome(t *testing.T, dir, home string) {
+	t.Helper()
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	oldHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		if err := os.Chdir(oldWd); err != nil {
+			t.Logf("Failed to restore working directory: %v", err)
+		}
+		if err := os.Setenv("HOME", oldHome); err != nil {
+			t.Logf("Failed to restore HOME environment: %v", err)
+		}
+	})
+
+	if err := os.Setenv("HOME", home); err != nil {
+		t.Fatalf("Failed to set HOME environment: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestConfigExtends(t *testing.T) {
+	tempDir := t.TempDir()
+	projectDir := filepath.Join(tempDir, "project")
+
+	writeTestFile(t, filepath.Join(tempDir, "shared", "base.json"), `{
+		"extends": ["core.json"],
+		"ignorePatterns": ["SHARED", "LOCAL"],
+		"fileIgnorePatterns": ["^generated/"]
+	}`)
+	writeTestFile(t, filepath.Join(tempDir, "shared", "core.json"), `{
+		"ignorePatterns": ["CORE"]
+	}`)
+	writeTestFile(t, filepath.Join(projectDir, ".nocmt.json"), `{
+		"extends": ["../shared/base.json", "keep-docs"],
+		"ignorePatterns": ["LOCAL"]
+	}`)
+
+	chdirWithHome(t, projectDir, filepath.Join(tempDir, "home"))
+
+	cfg := New()
+	if err := cfg.LoadConfigurations(); err != nil {
+		t.Fatalf("LoadConfigurations() error = %v", err)
+	}
+
+	effective := cfg.Effective()
+	if effective.IgnorePatterns[0] != "CORE" || effective.IgnorePatterns[1] != "SHARED" || effective.IgnorePatterns[2] != "LOCAL" {
+		t.Errorf("Effective() ignore patterns not ordered base-first: %v", effective.IgnorePatterns)
+	}
+	if strings.Count(strings.Join(effective.IgnorePatterns, "\n"), "LOCAL") != 1 {
+		t.Errorf("Effective() should deduplicate inherited patterns: %v", effective.IgnorePatterns)
+	}
+
+	for _, comment := range []string{"// CORE", "// SHARED", "// LOCAL", "/// Doc comment"} {
+		if !cfg.ShouldIgnoreComment(comment) {
+			t.Errorf("ShouldIgnoreComment(%q) = false, want true", comment)
+		}
+	}
+	if !cfg.ShouldIgnoreFile("generated/api.go") {
+		t.Errorf("ShouldIgnoreFile() should honor inherited file patterns")
+	}
+
+	var sources []string
+	for _, layer := range cfg.Layers() {
+		sources = append(sources, filepath.Base(layer.Source))
+	}
+	want := []string{"config.json", "core.json", "base.json", "preset:keep-docs", ".nocmt.json", "cli"}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("Layers() sources = %v, want %v", sources, want)
+	}
+}
+
+func TestConfigExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				".nocmt.json": `{"extends": ["a.json"]}`,
+				"a.json":      `{"extends": ["b.json"]}`,
+				"b.json":      `{"extends": ["a.json"]}`,
+			},
+			wantErr: "cycle",
+		},
+		{
+			name: "self reference",
+			files: map[string]string{
+				".nocmt.json": `{"extends": ["./.nocmt.json"]}`,
+			},
+			wantErr: "cycle",
+		},
+		{
+			name: "unknown preset",
+			files: map[string]string{
+				".nocmt.json": `{"extends": ["does-not-exist"]}`,
+			},
+			wantErr: "unknown config preset",
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				".nocmt.json": `{"extends": ["../missing.json"]}`,
+			},
+			wantErr: "cannot read extended config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			projectDir := filepath.Join(tempDir, "project")
+			for name, content := range tt.files {
+				writeTestFile(t, filepath.Join(projectDir, name), content)
+			}
+
+			chdirWithHome(t, projectDir, filepath.Join(tempDir, "home"))
+
+			cfg := New()
+			err := cfg.LoadConfigurations()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigurations() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPresetsAreValid(t *testing.T) {
+	names := PresetNames()
+	for _, want := range []string{"ai-cleanup", "keep-docs", "strict"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("PresetNames() = %v, missing %q", names, want)
+		}
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			_, preset, _, err := loadExtendedConfig(name, "")
+			if err != nil {
+				t.Fatalf("loadExtendedConfig(%q) error = %v", name, err)
+			}
+			cfg := New()
+			cfg.Local = preset
+			if err := cfg.compilePatterns(); err != nil {
+				t.Errorf("preset %q has invalid patterns: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestAddPatternDeduplicates(t *testing.T) {
+	tempDir := t.TempDir()
+	chdirWithHome(t, tempDir, filepath.Join(tempDir, "home"))
+
+	cfg := New()
+	for i := 0; i < 2; i++ {
+		if err := cfg.AddIgnorePattern("TODO"); err != nil {
+			t.Fatalf("AddIgnorePattern() error = %v", err)
+		}
+		if err := cfg.AddFileIgnorePattern("^vendor/"); err != nil {
+			t.Fatalf("AddFileIgnorePattern() error = %v", err)
+		}
+	}
+
+	if !reflect.DeepEqual(cfg.Local.IgnorePatterns, []string{"TODO"}) {
+		t.Errorf("IgnorePatterns = %v, want [TODO]", cfg.Local.IgnorePatterns)
+	}
+	if !reflect.DeepEqual(cfg.Local.FileIgnorePatterns, []string{"^vendor/"}) {
+		t.Errorf("FileIgnorePatterns = %v, want [^vendor/]", cfg.Local.FileIgnorePatterns)
+	}
+}
+
+func TestRemovePattern(t *testing.T) {
+	tempDir := t.TempDir()
+	homeDir := filepath.Join(tempDir, "home")
+	projectDir := filepath.Join(tempDir, "project")
+
+	writeTestFile(t, filepath.Join(homeDir, ".nocmt", "config.json"), `{"ignorePatterns": ["GLOBAL", "SHARED"]}`)
+	writeTestFile(t, filepath.Join(projectDir, ".nocmt.json"), `{
+		"extends": ["base.json"],
+		"ignorePatterns": ["LOCAL", "SHARED"],
+		"fileIgnorePatterns": ["^dist/"]
+	}`)
+	writeTestFile(t, filepath.Join(projectDir, "base.json"), `{"ignorePatterns": ["BASE"]}`)
+
+	chdirWithHome(t, projectDir, homeDir)
+
+	cfg := New()
+	if err := cfg.LoadConfigurations(); err != nil {
+		t.Fatalf("LoadConfigurations() error = %v", err)
+	}
+
+	source, err := cfg.RemoveIgnorePattern("SHARED", false)
+	if err != nil || source != ".nocmt.json" {
+		t.Errorf("RemoveIgnorePattern(SHARED) = %q, %v; want local config", source, err)
+	}
+
+	source, err = cfg.RemoveIgnorePattern("GLOBAL", false)
+	if err != nil || !strings.HasSuffix(source, "config.json") {
+		t.Errorf("RemoveIgnorePattern(GLOBAL) = %q, %v; want global config", source, err)
+	}
+
+	if _, err := cfg.RemoveIgnorePattern("LOCAL", true); err == nil {
+		t.Errorf("RemoveIgnorePattern(LOCAL, global) should fail when the pattern is only local")
+	}
+
+	if _, err := cfg.RemoveIgnorePattern("BASE", false); err == nil || !strings.Contains(err.Error(), "inherited") {
+		t.Errorf("RemoveIgnorePattern(BASE) error = %v, want inherited error", err)
+	}
+
+	if _, err := cfg.RemoveFileIgnorePattern("^dist/", false); err != nil {
+		t.Errorf("RemoveFileIgnorePattern() error = %v", err)
+	}
+
+	if cfg.ShouldIgnoreComment("// GLOBAL") || !cfg.ShouldIgnoreComment("// SHARED") {
+		t.Errorf("compiled patterns not refreshed after removal")
+	}
+
+	reloaded := New()
+	if err := reloaded.LoadConfigurations(); err != nil {
+		t.Fatalf("LoadConfigurations() error = %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.Local.IgnorePatterns, []string{"LOCAL"}) {
+		t.Errorf("local IgnorePatterns after removal = %v", reloaded.Local.IgnorePatterns)
+	}
+	if !reflect.DeepEqual(reloaded.Local.Extends, []string{"base.json"}) {
+		t.Errorf("saving local config must keep extends, got %v", reloaded.Local.Extends)
+	}
+	if !reflect.DeepEqual(reloaded.Global.IgnorePatterns, []string{"SHARED"}) {
+		t.Errorf("global IgnorePatterns after removal = %v", reloaded.Global.IgnorePatterns)
+	}
+}
+
+func TestPatternSourcesAndMatches(t *testing.T) {
+	cfg := New()
+	cfg.Global.IgnorePatterns = []string{"TODO", "WHY"}
+	cfg.Local.IgnorePatterns = []string{"TODO", "JIRA-\\d+"}
+	if err := cfg.SetCLIPatterns([]string{"NOTE"}); err != nil {
+		t.Fatalf("SetCLIPatterns() error = %v", err)
+	}
+
+	want := []PatternSource{
+		{Pattern: "TODO", Source: "global"},
+		{Pattern: "WHY", Source: "global"},
+		{Pattern: "JIRA-\\d+", Source: ".nocmt.json"},
+		{Pattern: "NOTE", Source: "cli"},
+	}
+	if got := cfg.PatternSources(IgnorePatternsOf); !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternSources() = %v, want %v", got, want)
+	}
+
+	matches := cfg.MatchingIgnorePatterns("// TODO JIRA-42")
+	if len(matches) != 2 || matches[0].Pattern != "TODO" || matches[1].Pattern != "JIRA-\\d+" {
+		t.Errorf("MatchingIgnorePatterns() = %v", matches)
+	}
+	if len(cfg.MatchingIgnorePatterns("// plain comment")) != 0 {
+		t.Errorf("MatchingIgnorePatterns() should not match a plain comment")
+	}
+}
+
+func TestRemovePatterns(t *testing.T) {
+	cfg := New()
+	if !cfg.IsRemovalCandidate("// anything") {
+		t.Errorf("IsRemovalCandidate() should accept every comment without remove patterns")
+	}
+
+	cfg.Local.RemovePatterns = []string{`^//\s*Here`}
+	if err := cfg.SetCLIRemovePatterns([]string{`Step \d`}); err != nil {
+		t.Fatalf("SetCLIRemovePatterns() error = %v", err)
+	}
+
+	tests := []struct {
+		comment string
+		want    bool
+	}{
+		{"// Here we loop", true},
+		{"// Step 3: return", true},
+		{"// Explains the invariant", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsRemovalCandidate(tt.comment); got != tt.want {
+			t.Errorf("IsRemovalCandidate(%q) = %v, want %v", tt.comment, got, tt.want)
+		}
+	}
+
+	if err := cfg.SetCLIRemovePatterns([]string{"("}); err == nil {
+		t.Errorf("SetCLIRemovePatterns() should reject invalid regex")
+	}
+}
+
+func TestRules(t *testing.T) {
+	no := false
+	cfg := New()
+	cfg.Global.Rules = []Rule{
+		{Name: "remove-long", Action: RuleActionRemove, MinLength: 20},
+	}
+	cfg.Local.Rules = []Rule{
+		{Name: "keep-go-why", Action: RuleActionKeep, Languages: []string{"go"}, Pattern: `(?i)\bwhy\b`},
+		{Name: "remove-trailing", Action: RuleActionRemove, Position: PositionTrailing, InsideFunction: &no},
+		{Name: "keep-blocks", Action: RuleActionKeep, MinLines: 3, MaxLines: 5},
+	}
+	if err := cfg.compilePatterns(); err != nil {
+		t.Fatalf("compilePatterns() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		facts    CommentFacts
+		wantRule string
+	}{
+		{"later layer wins", CommentFacts{Language: "go", Text: "// explains why this exists at length"}, "keep-go-why"},
+		{"language mismatch falls through", CommentFacts{Language: "python", Text: "# explains why this exists at length"}, "remove-long"},
+		{"position and function conditions", CommentFacts{Text: "// short", Trailing: true}, "remove-trailing"},
+		{"function condition excludes", CommentFacts{Text: "// short", Trailing: true, InsideFunction: true}, ""},
+		{"line bounds", CommentFacts{Text: "// a", Lines: 4}, "keep-blocks"},
+		{"line bounds exceeded", CommentFacts{Text: "// a", Lines: 6}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := cfg.MatchRule(tt.facts)
+			if tt.wantRule == "" {
+				if ok {
+					t.Errorf("MatchRule() matched %q, want no match", rule.Rule.Name)
+				}
+				return
+			}
+			if !ok || rule.Rule.Name != tt.wantRule {
+				t.Errorf("MatchRule() = %q (%v), want %q", rule.Rule.Name, ok, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestRuleValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"missing action", Rule{Pattern: "x"}},
+		{"unknown position", Rule{Action: RuleActionKeep, Position: "inline"}},
+		{"invalid pattern", Rule{Action: RuleActionRemove, Pattern: "("}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.Local.Rules = []Rule{tt.rule}
+			if err := cfg.compilePatterns(); err == nil {
+				t.Errorf("compilePatterns() accepted invalid rule %+v", tt.rule)
+			}
+		})
+	}
+}
+
+func TestQueries(t *testing.T) {
+	tempDir := t.TempDir()
+	projectDir := filepath.Join(tempDir, "project")
+
+	writeTestFile(t, filepath.Join(tempDir, "shared", "queries", "go.scm"), `((comment) @keep (#match? @keep "^// SHARED"))`)
+	writeTestFile(t, filepath.Join(tempDir, "shared", "base.json"), `{
+		"queries": [{"language": "go", "file": "queries/go.scm"}]
+	}`)
+	writeTestFile(t, filepath.Join(projectDir, ".nocmt.json"), `{
+		"extends": ["../shared/base.json"],
+		"queries": [
+			{"language": "go", "query": "(comment) @remove"},
+			{"language": "python", "query": "(comment) @keep"}
+		]
+	}`)
+
+	chdirWithHome(t, projectDir, filepath.Join(tempDir, "home"))
+
+	cfg := New()
+	if err := cfg.LoadConfigurations(); err != nil {
+		t.Fatalf("LoadConfigurations() error = %v", err)
+	}
+
+	got := cfg.QueriesFor("go")
+	want := []string{`((comment) @keep (#match? @keep "^// SHARED"))`, "(comment) @remove"}
+	if !slices.Equal(got, want) {
+		t.Errorf("QueriesFor(go) = %v, want %v", got, want)
+	}
+	if sources := cfg.QuerySources(); len(sources) != 3 || sources[0].Pattern != "go: queries/go.scm" {
+		t.Errorf("QuerySources() = %v", sources)
+	}
+
+	if err := cfg.SaveLocalConfig(); err != nil {
+		t.Fatalf("SaveLocalConfig() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".nocmt.json"))
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	if strings.Contains(string(data), "SHARED") {
+		t.Errorf("SaveLocalConfig() should not inline inherited query files: %s", data)
+	}
+}
+
+func TestQueryValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query QueryRule
+	}{
+		{"missing language", QueryRule{Query: "(comment) @keep"}},
+		{"missing query", QueryRule{Language: "go"}},
+		{"query and file", QueryRule{Language: "go", Query: "(comment) @keep", File: "go.scm"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := New()
+			cfg.Local.Queries = []QueryRule{tt.query}
+			if err := cfg.compilePatterns(); err == nil {
+				t.Errorf("compilePatterns() should reject %+v", tt.query)
+			}
+		})
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	cfg := New()
+	if mode, _ := cfg.Mode(); mode != ModeAll {
+		t.Errorf("Mode() = %q, want %q by default", mode, ModeAll)
+	}
+
+	if err := cfg.SetMode(ModeAIOnly, 0.5); err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+	if mode, threshold := cfg.Mode(); mode != ModeAIOnly || threshold != 0.5 {
+		t.Errorf("Mode() = %q, %v, want %q, 0.5", mode, threshold, ModeAIOnly)
+	}
+
+	if err := cfg.SetMode("aggressive", 0.5); err == nil {
+		t.Errorf("SetMode() should reject unknown modes")
+	}
+	if err := cfg.SetMode(ModeAIOnly, 1.5); err == nil {
+		t.Errorf("SetMode() should reject thresholds above 1")
+	}
+}
+
+func TestBudget(t *testing.T) {
+	overlay := mergeBudgets(&CommentBudget{MaxAddedCommentRatio: 0.2, MaxCommentLinesPerFunction: 5}, &CommentBudget{MaxAddedCommentRatio: 0.15})
+	if *overlay != (CommentBudget{MaxAddedCommentRatio: 0.15, MaxCommentLinesPerFunction: 5}) {
+		t.Errorf("mergeBudgets() = %+v, want the overlay ratio and the base function limit", *overlay)
+	}
+
+	cfg := New()
+	if cfg.Budget().Enabled() {
+		t.Errorf("Budget() should be disabled by default")
+	}
+	if err := cfg.SetCLIBudget(CommentBudget{MaxAddedCommentRatio: 0.15}); err != nil {
+		t.Fatalf("SetCLIBudget() error = %v", err)
+	}
+	if budget := cfg.Budget(); !budget.Enabled() || budget.MaxAddedCommentRatio != 0.15 {
+		t.Errorf("Budget() = %+v, want maxAddedCommentRatio 0.15", budget)
+	}
+	if err := cfg.SetCLIBudget(CommentBudget{MaxAddedCommentRatio: 1.5}); err == nil {
+		t.Errorf("SetCLIBudget() should reject ratios above 1")
+	}
+}
+
+func TestDocsPolicy(t *testing.T) {
+	cfg := New()
+	if policy := cfg.DocsPolicy("go"); policy != DocsAll {
+		t.Errorf("DocsPolicy() = %q, want %q by default", policy, DocsAll)
+	}
+
+	cfg.Global.Docs = map[string]string{"go": DocsAll}
+	cfg.Local.Docs = map[string]string{"go": DocsExportedOnly}
+	if err := cfg.compilePatterns(); err != nil {
+		t.Fatalf("compilePatterns() error = %v", err)
+	}
+	if policy := cfg.DocsPolicy("go"); policy != DocsExportedOnly {
+		t.Errorf("DocsPolicy() = %q, want the local policy %q", policy, DocsExportedOnly)
+	}
+
+	cfg.Local.Docs = map[string]string{"go": "public"}
+	if err := cfg.compilePatterns(); err == nil {
+		t.Errorf("compilePatterns() should reject unknown docs policies")
+	}
+	cfg.Local.Docs = map[string]string{"python": DocsExportedOnly}
+	if err := cfg.compilePatterns(); err == nil {
+		t.Errorf("compilePatterns() should reject Go policies for Python")
+	}
+	cfg.Local.Docs = map[string]string{"cobol": DocsAll}
+	if err := cfg.compilePatterns(); err == nil {
+		t.Errorf("compilePatterns() should reject languages without a docs policy")
+	}
+}
+
+func TestUnsafeMarkers(t *testing.T) {
+	cfg := New()
+	if markers := cfg.UnsafeMarkers(); !reflect.DeepEqual(markers, []string{"SAFETY:"}) {
+		t.Errorf("UnsafeMarkers() = %v, want only SAFETY: by default", markers)
+	}
+
+	cfg.Global.UnsafeMarkers = []string{"invariant:"}
+	cfg.Local.UnsafeMarkers = []string{"NOTE(clippy):", "invariant:"}
+	if err := cfg.compilePatterns(); err != nil {
+		t.Fatalf("compilePatterns() error = %v", err)
+	}
+	want := []string{"SAFETY:", "invariant:", "NOTE(clippy):"}
+	if markers := cfg.UnsafeMarkers(); !reflect.DeepEqual(markers, want) {
+		t.Errorf("UnsafeMarkers() = %v, want %v", markers, want)
+	}
+
+	cfg.Local.UnsafeMarkers = []string{" "}
+	if err := cfg.compilePatterns(); err == nil {
+		t.Errorf("compilePatterns() should reject empty unsafe markers")
+	}
+}
+
+func TestKeepMarkers(t *testing.T) {
+	cfg := New()
+	for _, comment := range []string{"// TODO: later", "# FIXME", "/* NOTE: slow */", "/**\n * HACK around the driver\n */"} {
+		if _, ok := cfg.MatchKeepMarker(comment); !ok {
+			t.Errorf("MatchKeepMarker(%q) should match a default marker", comment)
+		}
+	}
+	for _, comment := range []string{"// TODOS are tracked elsewhere", "// not a TODO", "// REVIEW: naming"} {
+		if marker, ok := cfg.MatchKeepMarker(comment); ok {
+			t.Errorf("MatchKeepMarker(%q) = %q, want no match", comment, marker)
+		}
+	}
+
+	cfg.Local.KeepMarkers = []string{"REVIEW"}
+	if err := cfg.compilePatterns(); err != nil {
+		t.Fatalf("compilePatterns() error = %v", err)
+	}
+	if marker, ok := cfg.MatchKeepMarker("// REVIEW: naming"); !ok || marker != "REVIEW" {
+		t.Errorf("MatchKeepMarker() = %q, %v, want the configured REVIEW marker", marker, ok)
+	}
+
+	if err := cfg.SetNoDefaultKeeps(true); err != nil {
+		t.Fatalf("SetNoDefaultKeeps() error = %v", err)
+	}
+	if markers := cfg.KeepMarkers(); !reflect.DeepEqual(markers, []string{"REVIEW"}) {
+		t.Errorf("KeepMarkers() = %v, want only the configured markers", markers)
+	}
+	if _, ok := cfg.MatchKeepMarker("// TODO: later"); ok {
+		t.Errorf("MatchKeepMarker() should not match TODO without default keeps")
+	}
+
+	cfg.Local.KeepMarkers = []string{""}
+	if err := cfg.compilePatterns(); err == nil {
+		t.Errorf("compilePatterns() should reject empty keep markers")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	DocsAll             = "all"
+	DocsExportedOnly    = "exported-only"
+	DocsRemovePrivate   = "remove-private"
+	DocsRemoveRestating = "remove-restating"
+	DocsFirstLine       = "first-line"
+)
+
+var docsPolicies = map[string][]string{
+	"go":     {DocsAll, DocsExportedOnly},
+	"python": {DocsAll, DocsRemovePrivate, DocsRemoveRestating, DocsFirstLine},
+}
+
+func validateDocs(docs map[string]string) error {
+	languages := make([]string, 0, len(docs))
+	for language := range docs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		policies, ok := docsPolicies[language]
+		if !ok {
+			return fmt.Errorf("docs policy is not supported for language '%s'", language)
+		}
+		if !slices.Contains(policies, docs[language]) {
+			return fmt.Errorf("unknown docs policy '%s' for %s (available: %s)", docs[language], language, strings.Join(policies, ", "))
+		}
+	}
+	return nil
+}
+
+func mergeDocs(base, overlay map[string]string) map[string]string {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for language, policy := range base {
+		merged[language] = policy
+	}
+	for language, policy := range overlay {
+		merged[language] = policy
+	}
+	return merged
+}
+
+func (c *Config) DocsPolicy(language string) string {
+	if c == nil {
+		return DocsAll
+	}
+	if policy, ok := c.docs[language]; ok {
+		return policy
+	}
+	return DocsAll
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed presets/*.json
+var presetFS embed.FS
+
+const presetSourcePrefix = "preset:"
+
+func PresetNames() []string {
+	entries, err := presetFS.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isPresetReference(ref string) bool {
+	return !strings.ContainsAny(ref, `/\`) && !strings.HasSuffix(ref, ".json")
+}
+
+func loadExtendedConfig(ref, baseDir string) (source string, cfg CommentConfig, dir string, err error) {
+	if isPresetReference(ref) {
+		data, err := presetFS.ReadFile("presets/" + ref + ".json")
+		if err != nil {
+			return "", cfg, "", fmt.Errorf("unknown config preset '%s' (available: %s)", ref, strings.Join(PresetNames(), ", "))
+		}
+		source = presetSourcePrefix + ref
+		cfg, err = parseConfig(data, source)
+		return source, cfg, baseDir, err
+	}
+
+	path := ref
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	path = filepath.Clean(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", cfg, "", fmt.Errorf("cannot read extended config %s: %w", ref, err)
+	}
+	cfg, err = parseConfig(data, path)
+	if err != nil {
+		return path, cfg, filepath.Dir(path), err
+	}
+	return path, cfg, filepath.Dir(path), resolveQueryFiles(&cfg, filepath.Dir(path))
+}
+
+func resolveExtends(cfg CommentConfig, baseDir string, chain []string) ([]Layer, error) {
+	var layers []Layer
+	for _, ref := range cfg.Extends {
+		source, extended, dir, err := loadExtendedConfig(ref, baseDir)
+		if err != nil {
+			return layers, err
+		}
+
+		if slices.Contains(chain, source) {
+			cycle := append(append([]string{}, chain...), source)
+			return layers, fmt.Errorf("config extends cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		nested, err := resolveExtends(extended, dir, append(chain, source))
+		layers = append(layers, nested...)
+		if err != nil {
+			return layers, err
+		}
+
+		layers = append(layers, Layer{Source: source, Config: extended})
+	}
+	return layers, nil
+}
+
+func mergeConfigs(base, overlay CommentConfig) CommentConfig {
+	return CommentConfig{
+		IgnorePatterns:     appendUnique(base.IgnorePatterns, overlay.IgnorePatterns),
+		FileIgnorePatterns: appendUnique(base.FileIgnorePatterns, overlay.FileIgnorePatterns),
+		RemovePatterns:     appendUnique(base.RemovePatterns, overlay.RemovePatterns),
+		Rules:              append(append([]Rule{}, base.Rules...), overlay.Rules...),
+		Queries:            append(append([]QueryRule{}, base.Queries...), overlay.Queries...),
+		Budget:             mergeBudgets(base.Budget, overlay.Budget),
+		Docs:               mergeDocs(base.Docs, overlay.Docs),
+		UnsafeMarkers:      appendUnique(base.UnsafeMarkers, overlay.UnsafeMarkers),
+		KeepMarkers:        appendUnique(base.KeepMarkers, overlay.KeepMarkers),
+		NoDefaultKeeps:     base.NoDefaultKeeps || overlay.NoDefaultKeeps,
+	}
+}
+
+func appendUnique(base, values []string) []string {
+	result := append([]string{}, base...)
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
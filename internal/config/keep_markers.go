@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var DefaultKeepMarkers = []string{"TODO", "FIXME", "NOTE", "HACK", "XXX", "BUG", "WARNING"}
+
+func compileKeepMarkers(markers []string) (*regexp.Regexp, error) {
+	if len(markers) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, 0, len(markers))
+	for _, marker := range markers {
+		if strings.TrimSpace(marker) == "" {
+			return nil, fmt.Errorf("keep marker must not be empty")
+		}
+		quoted = append(quoted, regexp.QuoteMeta(strings.TrimSpace(marker)))
+	}
+	return regexp.Compile(`(?m)^[\s/*#!;-]*(` + strings.Join(quoted, "|") + `)\b`)
+}
+
+func (c *Config) SetNoDefaultKeeps(disabled bool) error {
+	c.cliNoDefaultKeeps = disabled
+	return c.compilePatterns()
+}
+
+func (c *Config) KeepMarkers() []string {
+	if c == nil {
+		return nil
+	}
+	effective := c.Effective()
+	if effective.NoDefaultKeeps {
+		return effective.KeepMarkers
+	}
+	return appendUnique(DefaultKeepMarkers, effective.KeepMarkers)
+}
+
+func (c *Config) MatchKeepMarker(comment string) (string, bool) {
+	if c == nil || c.keepMarkers == nil {
+		return "", false
+	}
+	match := c.keepMarkers.FindStringSubmatch(comment)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	QueryCaptureKeep   = "keep"
+	QueryCaptureRemove = "remove"
+)
+
+type QueryRule struct {
+	Language    string `json:"language"`
+	Query       string `json:"query,omitempty"`
+	File        string `json:"file,omitempty"`
+	fileContent string
+}
+
+func (q QueryRule) Text() string {
+	if q.Query != "" {
+		return q.Query
+	}
+	return q.fileContent
+}
+
+func resolveQueryFiles(cfg *CommentConfig, dir string) error {
+	for i, query := range cfg.Queries {
+		if query.File == "" {
+			continue
+		}
+		path := query.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read query file %s: %w", query.File, err)
+		}
+		cfg.Queries[i].fileContent = string(data)
+	}
+	return nil
+}
+
+func validateQuery(query QueryRule) error {
+	if query.Language == "" {
+		return fmt.Errorf("query is missing a language")
+	}
+	if query.Query == "" && query.File == "" {
+		return fmt.Errorf("%s query needs either \"query\" or \"file\"", query.Language)
+	}
+	if query.Query != "" && query.File != "" {
+		return fmt.Errorf("%s query cannot set both \"query\" and \"file\"", query.Language)
+	}
+	return nil
+}
+
+func (c *Config) QueriesFor(language string) []string {
+	var queries []string
+	for _, layer := range c.Layers() {
+		for _, query := range layer.Config.Queries {
+			if query.Language == language && query.Text() != "" {
+				queries = append(queries, query.Text())
+			}
+		}
+	}
+	return queries
+}
+
+func (q QueryRule) Label() string {
+	if q.File != "" {
+		return fmt.Sprintf("%s: %s", q.Language, q.File)
+	}
+	return fmt.Sprintf("%s: %s", q.Language, strings.Join(strings.Fields(q.Query), " "))
+}
+
+func (c *Config) QuerySources() []PatternSource {
+	var sources []PatternSource
+	for _, layer := range c.Layers() {
+		for _, query := range layer.Config.Queries {
+			sources = append(sources, PatternSource{Pattern: query.Label(), Source: layer.Source})
+		}
+	}
+	return sources
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	RuleActionKeep   = "keep"
+	RuleActionRemove = "remove"
+
+	PositionFullLine = "full-line"
+	PositionTrailing = "trailing"
+)
+
+type Rule struct {
+	Name             string   `json:"name,omitempty"`
+	Action           string   `json:"action"`
+	Languages        []string `json:"languages,omitempty"`
+	Position         string   `json:"position,omitempty"`
+	MinLength        int      `json:"minLength,omitempty"`
+	MaxLength        int      `json:"maxLength,omitempty"`
+	MinLines         int      `json:"minLines,omitempty"`
+	MaxLines         int      `json:"maxLines,omitempty"`
+	AboveDeclaration *bool    `json:"aboveDeclaration,omitempty"`
+	InsideFunction   *bool    `json:"insideFunction,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+}
+
+type CommentFacts struct {
+	Language         string
+	Text             string
+	Trailing         bool
+	Lines            int
+	AboveDeclaration bool
+	InsideFunction   bool
+	QueryCapture     string
+	Code             string
+	CommentedCode    bool
+}
+
+type compiledRule struct {
+	Rule
+	source  string
+	pattern *regexp.Regexp
+}
+
+type RuleSource struct {
+	Rule   Rule
+	Source string
+}
+
+func compileRule(rule Rule, source string) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule, source: source}
+
+	if rule.Action != RuleActionKeep && rule.Action != RuleActionRemove {
+		return compiled, fmt.Errorf("rule %s: action must be %q or %q", rule.Label(), RuleActionKeep, RuleActionRemove)
+	}
+	if rule.Position != "" && rule.Position != PositionFullLine && rule.Position != PositionTrailing {
+		return compiled, fmt.Errorf("rule %s: position must be %q or %q", rule.Label(), PositionFullLine, PositionTrailing)
+	}
+	if rule.Pattern != "" {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return compiled, fmt.Errorf("rule %s: invalid pattern '%s': %w", rule.Label(), rule.Pattern, err)
+		}
+		compiled.pattern = pattern
+	}
+	return compiled, nil
+}
+
+func (r Rule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Describe()
+}
+
+func (r Rule) Describe() string {
+	conditions := []string{r.Action}
+	if len(r.Languages) > 0 {
+		conditions = append(conditions, "languages="+strings.Join(r.Languages, ","))
+	}
+	if r.Position != "" {
+		conditions = append(conditions, "position="+r.Position)
+	}
+	for _, bound := range []struct {
+		name  string
+		value int
+	}{
+		{"minLength", r.MinLength},
+		{"maxLength", r.MaxLength},
+		{"minLines", r.MinLines},
+		{"maxLines", r.MaxLines},
+	} {
+		if bound.value > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s=%d", bound.name, bound.value))
+		}
+	}
+	if r.AboveDeclaration != nil {
+		conditions = append(conditions, fmt.Sprintf("aboveDeclaration=%t", *r.AboveDeclaration))
+	}
+	if r.InsideFunction != nil {
+		conditions = append(conditions, fmt.Sprintf("insideFunction=%t", *r.InsideFunction))
+	}
+	if r.Pattern != "" {
+		conditions = append(conditions, "pattern="+r.Pattern)
+	}
+	return strings.Join(conditions, " ")
+}
+
+func (r compiledRule) matches(facts CommentFacts) bool {
+	if len(r.Languages) > 0 && !slices.Contains(r.Languages, facts.Language) {
+		return false
+	}
+	if r.Position == PositionFullLine && facts.Trailing || r.Position == PositionTrailing && !facts.Trailing {
+		return false
+	}
+
+	length := utf8.RuneCountInString(strings.TrimSpace(facts.Text))
+	if r.MinLength > 0 && length < r.MinLength || r.MaxLength > 0 && length > r.MaxLength {
+		return false
+	}
+	if r.MinLines > 0 && facts.Lines < r.MinLines || r.MaxLines > 0 && facts.Lines > r.MaxLines {
+		return false
+	}
+
+	if r.AboveDeclaration != nil && *r.AboveDeclaration != facts.AboveDeclaration {
+		return false
+	}
+	if r.InsideFunction != nil && *r.InsideFunction != facts.InsideFunction {
+		return false
+	}
+
+	return r.pattern == nil || r.pattern.MatchString(facts.Text)
+}
+
+func (c *Config) MatchRule(facts CommentFacts) (RuleSource, bool) {
+	for i := len(c.compiledRules) - 1; i >= 0; i-- {
+		rule := c.compiledRules[i]
+		if rule.matches(facts) {
+			return RuleSource{Rule: rule.Rule, Source: rule.source}, true
+		}
+	}
+	return RuleSource{}, false
+}
+
+func (c *Config) RuleSources() []RuleSource {
+	var sources []RuleSource
+	for _, layer := range c.Layers() {
+		for _, rule := range layer.Config.Rules {
+			sources = append(sources, RuleSource{Rule: rule, Source: layer.Source})
+		}
+	}
+	return sources
+}
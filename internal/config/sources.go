@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+type PatternSource struct {
+	Pattern string
+	Source  string
+}
+
+func IgnorePatternsOf(cfg *CommentConfig) *[]string {
+	return &cfg.IgnorePatterns
+}
+
+func FileIgnorePatternsOf(cfg *CommentConfig) *[]string {
+	return &cfg.FileIgnorePatterns
+}
+
+func RemovePatternsOf(cfg *CommentConfig) *[]string {
+	return &cfg.RemovePatterns
+}
+
+func UnsafeMarkersOf(cfg *CommentConfig) *[]string {
+	return &cfg.UnsafeMarkers
+}
+
+func (c *Config) PatternSources(list func(*CommentConfig) *[]string) []PatternSource {
+	var sources []PatternSource
+	seen := make(map[string]bool)
+	for _, layer := range c.Layers() {
+		for _, pattern := range *list(&layer.Config) {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+			sources = append(sources, PatternSource{Pattern: pattern, Source: layer.Source})
+		}
+	}
+	return sources
+}
+
+func (c *Config) MatchingIgnorePatterns(comment string) []PatternSource {
+	return c.matchingPatterns(comment, IgnorePatternsOf)
+}
+
+func (c *Config) MatchingRemovePatterns(comment string) []PatternSource {
+	return c.matchingPatterns(comment, RemovePatternsOf)
+}
+
+func (c *Config) matchingPatterns(comment string, list func(*CommentConfig) *[]string) []PatternSource {
+	var matches []PatternSource
+	for _, source := range c.PatternSources(list) {
+		re, err := regexp.Compile(source.Pattern)
+		if err == nil && re.MatchString(comment) {
+			matches = append(matches, source)
+		}
+	}
+	return matches
+}
+
+func (c *Config) RemoveIgnorePattern(pattern string, global bool) (string, error) {
+	return c.RemovePatternFrom(IgnorePatternsOf, pattern, global)
+}
+
+func (c *Config) RemoveFileIgnorePattern(pattern string, global bool) (string, error) {
+	return c.RemovePatternFrom(FileIgnorePatternsOf, pattern, global)
+}
+
+func (c *Config) RemovePatternFrom(list func(*CommentConfig) *[]string, pattern string, global bool) (string, error) {
+	if !global {
+		if removeFromList(list(&c.Local), pattern) {
+			return localConfigFile, c.saveAndRecompile(c.SaveLocalConfig)
+		}
+	}
+
+	if removeFromList(list(&c.Global), pattern) {
+		return c.globalSource(), c.saveAndRecompile(c.SaveGlobalConfig)
+	}
+
+	for _, source := range c.PatternSources(list) {
+		if source.Pattern == pattern {
+			return "", fmt.Errorf("pattern '%s' is inherited from %s; edit that file instead", pattern, source.Source)
+		}
+	}
+	return "", fmt.Errorf("pattern '%s' not found in configuration", pattern)
+}
+
+func (c *Config) saveAndRecompile(save func() error) error {
+	if err := save(); err != nil {
+		return err
+	}
+	return c.compilePatterns()
+}
+
+func removeFromList(list *[]string, pattern string) bool {
+	index := slices.Index(*list, pattern)
+	if index < 0 {
+		return false
+	}
+	*list = slices.Delete(*list, index, index+1)
+	return true
+}
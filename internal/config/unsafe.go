@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+var DefaultUnsafeMarkers = []string{"SAFETY:"}
+
+func validateUnsafeMarkers(markers []string) error {
+	for _, marker := range markers {
+		if strings.TrimSpace(marker) == "" {
+			return fmt.Errorf("unsafe marker must not be empty")
+		}
+	}
+	return nil
+}
+
+func (c *Config) UnsafeMarkers() []string {
+	if c == nil {
+		return DefaultUnsafeMarkers
+	}
+	return appendUnique(DefaultUnsafeMarkers, c.unsafeMarkers)
+}
@@ -4,6 +4,9 @@ import (
 	"regexp"
 	"strings"
 
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/bash"
 )
 
@@ -26,6 +29,10 @@ func (p *BashProcessor) PreserveDirectives() bool {
 	return p.preserveDirectives
 }
 
+func (p *BashProcessor) protectedReason(node *sitter.Node, source string) string {
+	return licenseOrAnnotationComment(node, source, p.GetLanguageName())
+}
+
 func (p *BashProcessor) StripComments(source string) (string, error) {
 	shebangRegex := regexp.MustCompile(`^(#!.*)$`)
 	lines := strings.Split(source, "\n")
@@ -51,6 +58,12 @@ func (p *BashProcessor) StripComments(source string) (string, error) {
 		if p.preserveDirectives && p.isBashDirective(lines[lineIdx]) {
 			continue
 		}
+		if inLicenseHeader(source, int(r.StartByte)) {
+			continue
+		}
+		if keptByCommentConfig(p.commentConfig, commentFactsOf(r, p.GetLanguageName())) {
+			continue
+		}
 		filteredRanges = append(filteredRanges, r)
 	}
 
@@ -90,9 +103,13 @@ func (p *BashProcessor) StripComments(source string) (string, error) {
 func (p *BashProcessor) fallbackStripComments(source string) (string, error) {
 	lines := strings.Split(source, "\n")
 	var resultLines []string
+	headerEnd := licenseHeaderEnd(source, "#")
 
+	offset := 0
 	for i, line := range lines {
-		if i == 0 && strings.HasPrefix(strings.TrimSpace(line), "#!") {
+		inHeader := offset < headerEnd
+		offset += len(line) + 1
+		if i == 0 && strings.HasPrefix(strings.TrimSpace(line), "#!") || inHeader {
 			resultLines = append(resultLines, line)
 			continue
 		}
@@ -103,6 +120,10 @@ func (p *BashProcessor) fallbackStripComments(source string) (string, error) {
 		}
 
 		cleaned := p.removeLineComment(line)
+		if comment := line[len(cleaned):]; comment != "" && keptByCommentConfig(p.commentConfig, config.CommentFacts{Language: p.GetLanguageName(), Text: comment}) {
+			resultLines = append(resultLines, line)
+			continue
+		}
 		trimmed := strings.TrimRight(cleaned, " \t")
 		if trimmed == "" {
 			if strings.TrimSpace(line) == "" {
@@ -166,5 +187,5 @@ func (p *BashProcessor) removeLineComment(line string) string {
 
 func (p *BashProcessor) isBashDirective(line string) bool {
 	trimmed := strings.TrimSpace(line)
-	return strings.HasPrefix(trimmed, "# shellcheck")
+	return strings.HasPrefix(trimmed, "#") && isSuppressionComment("bash", trimmed)
 }
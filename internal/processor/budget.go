@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type FunctionSpan struct {
+	Name      string
+	StartLine int
+	EndLine   int
+}
+
+type FunctionComments struct {
+	FunctionSpan
+	CommentLines int
+}
+
+type AddedComments struct {
+	AddedLines        int
+	AddedCommentLines int
+	Functions         []FunctionComments
+}
+
+type FunctionFinder interface {
+	Functions(source string) ([]FunctionSpan, error)
+}
+
+func (p *SingleLineCoreProcessor) Functions(source string) ([]FunctionSpan, error) {
+	return functionSpans(p.lang, p.langName, source)
+}
+
+func Functions(proc LanguageProcessor, source string) ([]FunctionSpan, error) {
+	if finder, ok := proc.(FunctionFinder); ok {
+		return finder.Functions(source)
+	}
+	return functionSpans(languageForProcessor(proc), proc.GetLanguageName(), source)
+}
+
+func functionSpans(lang *sitter.Language, langName, source string) ([]FunctionSpan, error) {
+	if lang == nil {
+		return nil, fmt.Errorf("no tree-sitter parser available for language: %s", langName)
+	}
+
+	parser := parsers.Get(lang)
+	defer parsers.Put(lang, parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, []byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source for %s: %w", langName, err)
+	}
+	defer tree.Close()
+
+	var spans []FunctionSpan
+	Walk(tree.RootNode(), func(node *sitter.Node) bool {
+		if functionNodeTypes[node.Type()] {
+			name := "anonymous function"
+			if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+				name = source[nameNode.StartByte():nameNode.EndByte()]
+			}
+			spans = append(spans, FunctionSpan{
+				Name:      name,
+				StartLine: int(node.StartPoint().Row) + 1,
+				EndLine:   int(node.EndPoint().Row) + 1,
+			})
+		}
+		return true
+	})
+	return spans, nil
+}
+
+func MeasureAddedComments(proc LanguageProcessor, source string, modifiedLines map[int]bool, commentConfig *config.Config) (AddedComments, error) {
+	comments, err := InspectComments(proc, source, commentConfig)
+	if err != nil {
+		return AddedComments{}, err
+	}
+	functions, err := Functions(proc, source)
+	if err != nil {
+		return AddedComments{}, err
+	}
+
+	lines := strings.Split(source, "\n")
+	commentLines := make(map[int]bool)
+	for _, comment := range comments {
+		if comment.Kind == CommentKindDirective {
+			continue
+		}
+		textLines := strings.Split(comment.Text, "\n")
+		first := strings.TrimSpace(textLines[0])
+		last := strings.TrimSpace(textLines[len(textLines)-1])
+		for line := comment.StartLine; line <= comment.EndLine && line <= len(lines); line++ {
+			content := strings.TrimSpace(lines[line-1])
+			if content == "" {
+				continue
+			}
+			if line == comment.StartLine && first != "" && strings.Index(content, first) > 0 {
+				continue
+			}
+			if line == comment.EndLine && last != "" {
+				if index := strings.LastIndex(content, last); index != -1 && index+len(last) < len(content) {
+					continue
+				}
+			}
+			commentLines[line] = true
+		}
+	}
+
+	var measured AddedComments
+	perFunction := make([]int, len(functions))
+	for line := range modifiedLines {
+		if line < 1 || line > len(lines) || strings.TrimSpace(lines[line-1]) == "" {
+			continue
+		}
+		measured.AddedLines++
+		if !commentLines[line] {
+			continue
+		}
+		measured.AddedCommentLines++
+		if index := innermostFunction(functions, commentLines, line); index != -1 {
+			perFunction[index]++
+		}
+	}
+
+	for i, count := range perFunction {
+		if count > 0 {
+			measured.Functions = append(measured.Functions, FunctionComments{FunctionSpan: functions[i], CommentLines: count})
+		}
+	}
+	return measured, nil
+}
+
+func innermostFunction(functions []FunctionSpan, commentLines map[int]bool, line int) int {
+	found := -1
+	for i, function := range functions {
+		start := function.StartLine
+		for commentLines[start-1] {
+			start--
+		}
+		if line < start || line > function.EndLine {
+			continue
+		}
+		if found == -1 || function.EndLine-function.StartLine < functions[found].EndLine-functions[found].StartLine {
+			found = i
+		}
+	}
+	return found
+}
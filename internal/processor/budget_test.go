@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureAddedComments(t *testing.T) {
+	input := `package main
+
+//go:generate stringer -type=Kind
+
+// Run starts the server.
+// It blocks until the context is done.
+func Run() {
+	// listen on the port
+	listen()
+
+	handler := func() {
+		// serve one request
+		serve()
+	}
+	handler()
+}
+`
+	allLines := make(map[int]bool)
+	for line := 1; line <= 16; line++ {
+		allLines[line] = true
+	}
+
+	added, err := MeasureAddedComments(NewGoProcessor(true), input, allLines, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, added.AddedLines)
+	assert.Equal(t, 4, added.AddedCommentLines)
+	assert.Equal(t, []FunctionComments{
+		{FunctionSpan: FunctionSpan{Name: "Run", StartLine: 7, EndLine: 16}, CommentLines: 3},
+		{FunctionSpan: FunctionSpan{Name: "anonymous function", StartLine: 11, EndLine: 14}, CommentLines: 1},
+	}, added.Functions)
+
+	added, err = MeasureAddedComments(NewGoProcessor(true), input, map[int]bool{8: true, 9: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added.AddedLines)
+	assert.Equal(t, 1, added.AddedCommentLines)
+}
+
+func TestMeasureAddedCommentsSkipsTrailingComments(t *testing.T) {
+	input := `package main
+
+func Run() {
+	listen() // listen on the port
+	/* inline */ serve()
+	// serve one request
+	serve()
+}
+`
+	allLines := make(map[int]bool)
+	for line := 1; line <= 8; line++ {
+		allLines[line] = true
+	}
+
+	added, err := MeasureAddedComments(NewGoProcessor(true), input, allLines, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, added.AddedLines)
+	assert.Equal(t, 1, added.AddedCommentLines)
+	assert.Equal(t, []FunctionComments{
+		{FunctionSpan: FunctionSpan{Name: "Run", StartLine: 3, EndLine: 8}, CommentLines: 1},
+	}, added.Functions)
+}
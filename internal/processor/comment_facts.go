@@ -0,0 +1,201 @@
+package processor
+
+import (
+	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+var commentNodeTypes = map[string]bool{
+	"comment":               true,
+	"line_comment":          true,
+	"block_comment":         true,
+	"documentation_comment": true,
+	"doc_comment":           true,
+	"multiline_comment":     true,
+}
+
+var declarationNodeTypes = map[string]bool{
+	"function_declaration":                true,
+	"method_declaration":                  true,
+	"type_declaration":                    true,
+	"function_definition":                 true,
+	"class_definition":                    true,
+	"decorated_definition":                true,
+	"class_declaration":                   true,
+	"abstract_class_declaration":          true,
+	"interface_declaration":               true,
+	"enum_declaration":                    true,
+	"struct_declaration":                  true,
+	"record_declaration":                  true,
+	"constructor_declaration":             true,
+	"protocol_declaration":                true,
+	"object_declaration":                  true,
+	"type_alias_declaration":              true,
+	"generator_function_declaration":      true,
+	"method_definition":                   true,
+	"function_item":                       true,
+	"struct_item":                         true,
+	"enum_item":                           true,
+	"trait_item":                          true,
+	"impl_item":                           true,
+	"type_item":                           true,
+	"union_item":                          true,
+	"class_specifier":                     true,
+	"struct_specifier":                    true,
+	"namespace_definition":                true,
+	"trait_declaration":                   true,
+	"annotation_type_declaration":         true,
+	"function_signature":                  true,
+	"property_declaration":                true,
+	"field_declaration":                   true,
+	"method_signature":                    true,
+	"abstract_method_signature":           true,
+	"template_declaration":                true,
+	"secondary_constructor":               true,
+	"companion_object":                    true,
+	"init_declaration":                    true,
+	"extension_declaration":               true,
+	"typealias_declaration":               true,
+	"delegate_declaration":                true,
+	"event_declaration":                   true,
+	"operator_declaration":                true,
+	"destructor_declaration":              true,
+	"local_function_statement":            true,
+	"annotation_type_element_declaration": true,
+}
+
+var functionNodeTypes = map[string]bool{
+	"function_declaration":                   true,
+	"method_declaration":                     true,
+	"func_literal":                           true,
+	"function_definition":                    true,
+	"function_item":                          true,
+	"closure_expression":                     true,
+	"method_definition":                      true,
+	"function_expression":                    true,
+	"function":                               true,
+	"arrow_function":                         true,
+	"generator_function":                     true,
+	"generator_function_declaration":         true,
+	"constructor_declaration":                true,
+	"lambda_expression":                      true,
+	"lambda_literal":                         true,
+	"anonymous_function":                     true,
+	"lambda":                                 true,
+	"init_declaration":                       true,
+	"local_function_statement":               true,
+	"anonymous_function_creation_expression": true,
+}
+
+func isCommentNodeType(nodeType string) bool {
+	return commentNodeTypes[nodeType]
+}
+
+func describeComment(node *sitter.Node, source string) config.CommentFacts {
+	first, last := commentBlockBounds(node, source)
+	return config.CommentFacts{
+		Text:             source[node.StartByte():node.EndByte()],
+		Trailing:         isTrailingComment(node, source),
+		Lines:            int(last.EndPoint().Row-first.StartPoint().Row) + 1,
+		AboveDeclaration: precedesDeclaration(last),
+		InsideFunction:   hasFunctionAncestor(node),
+		Code:             describedCode(node, last, source),
+	}
+}
+
+func describedCode(node, last *sitter.Node, source string) string {
+	start := int(node.StartByte())
+	lineStart := findLineStartBeforePosition(source, start)
+	if !isOnlyWhitespaceBeforePosition(source, lineStart, start) {
+		return strings.TrimSpace(source[lineStart:start])
+	}
+
+	for next := last.NextNamedSibling(); next != nil; next = next.NextNamedSibling() {
+		if isCommentNodeType(next.Type()) {
+			continue
+		}
+		statement, _, _ := strings.Cut(source[next.StartByte():next.EndByte()], "\n")
+		return strings.TrimSpace(statement)
+	}
+	return ""
+}
+
+func commentFactsOf(comment CommentRange, language string) config.CommentFacts {
+	facts := comment.Facts
+	facts.Language = language
+	if facts.Text == "" {
+		facts.Text = comment.Content
+	}
+	return facts
+}
+
+func isTrailingComment(node *sitter.Node, source string) bool {
+	start := int(node.StartByte())
+	return !isOnlyWhitespaceBeforePosition(source, findLineStartBeforePosition(source, start), start)
+}
+
+func commentBlockBounds(node *sitter.Node, source string) (first, last *sitter.Node) {
+	first, last = node, node
+	if isTrailingComment(node, source) {
+		return first, last
+	}
+
+	for prev := first.PrevNamedSibling(); isAdjacentLineComment(prev, first, source); prev = first.PrevNamedSibling() {
+		first = prev
+	}
+	for next := last.NextNamedSibling(); isAdjacentLineComment(next, last, source) && !isTrailingComment(next, source); next = last.NextNamedSibling() {
+		last = next
+	}
+	return first, last
+}
+
+func isAdjacentLineComment(candidate, anchor *sitter.Node, source string) bool {
+	if candidate == nil || !isCommentNodeType(candidate.Type()) {
+		return false
+	}
+	if candidate.StartByte() < anchor.StartByte() {
+		return candidate.EndPoint().Row+1 == anchor.StartPoint().Row && !isTrailingComment(candidate, source)
+	}
+	return anchor.EndPoint().Row+1 == candidate.StartPoint().Row
+}
+
+func precedesDeclaration(comment *sitter.Node) bool {
+	previous := comment
+	for next := comment.NextNamedSibling(); next != nil; next = next.NextNamedSibling() {
+		if next.StartPoint().Row != previous.EndPoint().Row+1 {
+			return false
+		}
+		if next.Type() == "attribute_item" {
+			previous = next
+			continue
+		}
+		return isDeclarationNode(next)
+	}
+	return false
+}
+
+func isDeclarationNode(node *sitter.Node) bool {
+	if declarationNodeTypes[node.Type()] {
+		return true
+	}
+	if node.Type() == "export_statement" {
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if declarationNodeTypes[node.NamedChild(i).Type()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasFunctionAncestor(node *sitter.Node) bool {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if functionNodeTypes[parent.Type()] {
+			return true
+		}
+	}
+	return false
+}
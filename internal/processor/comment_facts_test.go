@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeComment(t *testing.T) {
+	source := `package main
+
+// Server handles requests.
+// It is safe for concurrent use.
+type Server struct{}
+
+// Run starts the server.
+func Run() {
+	// step one
+	start() // trailing note
+
+	// detached comment
+
+	stop()
+}
+`
+	parser := parsers.Get(golang.GetLanguage())
+	defer parsers.Put(golang.GetLanguage(), parser)
+	tree, err := parser.ParseCtx(context.Background(), nil, []byte(source))
+	assert.NoError(t, err)
+	defer tree.Close()
+
+	facts := map[string]config.CommentFacts{}
+	Walk(tree.RootNode(), func(node *sitter.Node) bool {
+		if isCommentNodeType(node.Type()) {
+			f := describeComment(node, source)
+			facts[f.Text] = f
+		}
+		return true
+	})
+
+	assert.Equal(t, config.CommentFacts{Text: "// Server handles requests.", Lines: 2, AboveDeclaration: true, Code: "type Server struct{}"}, facts["// Server handles requests."])
+	assert.Equal(t, config.CommentFacts{Text: "// It is safe for concurrent use.", Lines: 2, AboveDeclaration: true, Code: "type Server struct{}"}, facts["// It is safe for concurrent use."])
+	assert.Equal(t, config.CommentFacts{Text: "// Run starts the server.", Lines: 1, AboveDeclaration: true, Code: "func Run() {"}, facts["// Run starts the server."])
+	assert.Equal(t, config.CommentFacts{Text: "// step one", Lines: 1, InsideFunction: true, Code: "start()"}, facts["// step one"])
+	assert.Equal(t, config.CommentFacts{Text: "// trailing note", Trailing: true, Lines: 1, InsideFunction: true, Code: "start()"}, facts["// trailing note"])
+	assert.Equal(t, config.CommentFacts{Text: "// detached comment", Lines: 1, InsideFunction: true, Code: "stop()"}, facts["// detached comment"])
+}
+
+func TestRulesInStripComments(t *testing.T) {
+	yes := true
+	cfg := config.New()
+	cfg.Local.Rules = []config.Rule{
+		{Name: "keep-declaration-docs", Action: config.RuleActionKeep, AboveDeclaration: &yes},
+		{Name: "drop-long-python", Action: config.RuleActionRemove, Languages: []string{"python"}, MinLength: 10},
+		{Name: "keep-short-trailing", Action: config.RuleActionKeep, Position: config.PositionTrailing, MaxLength: 8},
+	}
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"KEEP"}))
+
+	goInput := `package main
+
+// Run starts the server.
+func Run() {
+	// step one
+	start() // ok
+	stop() // this trailing comment goes
+}
+`
+	goExpected := `package main
+
+// Run starts the server.
+func Run() {
+	start() // ok
+	stop()
+}
+`
+	goProcessor := NewGoProcessor(true)
+	goProcessor.SetCommentConfig(cfg)
+	actual, err := goProcessor.StripComments(goInput)
+	assert.NoError(t, err)
+	assert.Equal(t, goExpected, actual)
+
+	pyInput := `# KEEP but long enough to be removed by rule
+# KEEP
+x = 1
+`
+	pyExpected := `# KEEP
+x = 1
+`
+	pyProcessor := NewPythonSingleProcessor(true)
+	pyProcessor.SetCommentConfig(cfg)
+	actual, err = pyProcessor.StripComments(pyInput)
+	assert.NoError(t, err)
+	assert.Equal(t, pyExpected, actual)
+}
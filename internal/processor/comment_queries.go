@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"fmt"
+	"sync"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type queryKey struct {
+	lang *sitter.Language
+	text string
+}
+
+type QueryCacheType struct {
+	sync.Mutex
+	queries map[queryKey]*sitter.Query
+}
+
+var commentQueries = &QueryCacheType{
+	queries: make(map[queryKey]*sitter.Query),
+}
+
+func (c *QueryCacheType) Get(lang *sitter.Language, text string) (*sitter.Query, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	key := queryKey{lang: lang, text: text}
+	if query, exists := c.queries[key]; exists {
+		return query, nil
+	}
+
+	query, err := sitter.NewQuery([]byte(text), lang)
+	if err != nil {
+		return nil, err
+	}
+	if !hasDecisionCapture(query) {
+		query.Close()
+		return nil, fmt.Errorf("query must use a @%s or @%s capture", config.QueryCaptureKeep, config.QueryCaptureRemove)
+	}
+	c.queries[key] = query
+	return query, nil
+}
+
+func hasDecisionCapture(query *sitter.Query) bool {
+	for id := range query.CaptureCount() {
+		name := query.CaptureNameForId(id)
+		if name == config.QueryCaptureKeep || name == config.QueryCaptureRemove {
+			return true
+		}
+	}
+	return false
+}
+
+func queryCaptures(cfg *config.Config, language string, lang *sitter.Language, root *sitter.Node, source string) (map[uint32]string, error) {
+	if cfg == nil || lang == nil {
+		return nil, nil
+	}
+	texts := cfg.QueriesFor(language)
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	captures := make(map[uint32]string)
+	input := []byte(source)
+	for _, text := range texts {
+		query, err := commentQueries.Get(lang, text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s query: %w", language, err)
+		}
+
+		cursor := sitter.NewQueryCursor()
+		cursor.Exec(query, root)
+		for {
+			match, ok := cursor.NextMatch()
+			if !ok {
+				break
+			}
+			match = cursor.FilterPredicates(match, input)
+			for _, capture := range match.Captures {
+				name := query.CaptureNameForId(capture.Index)
+				if name != config.QueryCaptureKeep && name != config.QueryCaptureRemove {
+					continue
+				}
+				if isCommentNodeType(capture.Node.Type()) {
+					captures[capture.Node.StartByte()] = name
+				}
+			}
+		}
+		cursor.Close()
+	}
+	return captures, nil
+}
+
+func annotateQueryCaptures(ranges []CommentRange, captures map[uint32]string) {
+	if len(captures) == 0 {
+		return
+	}
+	for i := range ranges {
+		ranges[i].Facts.QueryCapture = captures[ranges[i].StartByte]
+	}
+}
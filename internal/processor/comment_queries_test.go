@@ -0,0 +1,101 @@
+package processor
+
+import (
+	"testing"
+
+	"nocmt/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueriesInStripComments(t *testing.T) {
+	cfg := config.New()
+	cfg.Local.Queries = []config.QueryRule{
+		{Language: "go", Query: `(function_declaration body: (block (comment) @remove))`},
+		{Language: "go", Query: `((comment) @keep (#match? @keep "^// step"))`},
+		{Language: "javascript", Query: `((comment) @remove (#match? @remove "^/\\*"))`},
+	}
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"KEEP"}))
+
+	goInput := `package main
+
+// KEEP this one
+func Run() {
+	// KEEP is overridden by the query
+	// step one stays
+	start()
+}
+`
+	goExpected := `package main
+
+// KEEP this one
+func Run() {
+	// step one stays
+	start()
+}
+`
+	goProcessor := NewGoProcessor(true)
+	goProcessor.SetCommentConfig(cfg)
+	actual, err := goProcessor.StripComments(goInput)
+	assert.NoError(t, err)
+	assert.Equal(t, goExpected, actual)
+
+	jsInput := `/*
+ * KEEP block goes anyway
+ */
+const a = /* inline */ 1;
+// KEEP line
+`
+	jsExpected := `const a = 1;
+// KEEP line
+`
+	jsProcessor := NewJavaScriptProcessor(true)
+	jsProcessor.SetCommentConfig(cfg)
+	actual, err = jsProcessor.StripComments(jsInput)
+	assert.NoError(t, err)
+	assert.Equal(t, jsExpected, actual)
+}
+
+func TestQueriesInSelectiveMode(t *testing.T) {
+	cfg := config.New()
+	cfg.Local.Queries = []config.QueryRule{
+		{Language: "go", Query: `((comment) @keep (#eq? @keep "// pinned"))`},
+	}
+
+	input := `package main
+
+// pinned
+// dropped
+func Run() {}
+`
+	expected := `package main
+
+// pinned
+
+func Run() {}
+`
+	actual, err := SelectivelyStripComments(input, "main.go", NewGoProcessor(true), map[int]bool{3: true, 4: true}, true, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestInvalidQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"syntax error", `((comment) @keep`},
+		{"no decision capture", `(comment) @other`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New()
+			cfg.Local.Queries = []config.QueryRule{{Language: "go", Query: tt.query}}
+			processor := NewGoProcessor(true)
+			processor.SetCommentConfig(cfg)
+			_, err := processor.StripComments("package main\n\n// a\nfunc main() {}\n")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package processor
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type CommentedCodeBlock struct {
+	StartLine int
+	EndLine   int
+	Code      string
+}
+
+type CommentedCodeFinder interface {
+	CommentedCodeBlocks(source string) ([]CommentedCodeBlock, error)
+}
+
+var lineCommentMarkers = []string{"///", "//", "#", "--"}
+
+const codePunctuation = "(){}[];=<>"
+
+var proseLine = regexp.MustCompile(`^[A-Z][a-z]+(\s+[a-z]+){2}|[.!?]$|^(TODO|FIXME|NOTE|HACK|XXX)\b`)
+
+type commentedCodeRun struct {
+	first *sitter.Node
+	last  *sitter.Node
+	code  string
+}
+
+type commentedCodeDetector struct {
+	lang     *sitter.Language
+	source   string
+	blocks   map[uint32]bool
+	verdicts map[uint32]bool
+	runs     []commentedCodeRun
+}
+
+func newCommentedCodeDetector(lang *sitter.Language, source string) *commentedCodeDetector {
+	return &commentedCodeDetector{
+		lang:     lang,
+		source:   source,
+		blocks:   make(map[uint32]bool),
+		verdicts: make(map[uint32]bool),
+	}
+}
+
+func commentedCodeDetectorFor(cfg *config.Config, lang *sitter.Language, source string) *commentedCodeDetector {
+	if cfg == nil || lang == nil {
+		return nil
+	}
+	if mode, _ := cfg.Mode(); mode != config.ModeCommentedCode {
+		return nil
+	}
+	return newCommentedCodeDetector(lang, source)
+}
+
+func annotateCommentedCode(cfg *config.Config, lang *sitter.Language, root *sitter.Node, source string, ranges []CommentRange) {
+	detector := commentedCodeDetectorFor(cfg, lang, source)
+	if detector == nil {
+		return
+	}
+
+	byStart := make(map[uint32]int, len(ranges))
+	for i, r := range ranges {
+		byStart[r.StartByte] = i
+	}
+	Walk(root, func(node *sitter.Node) bool {
+		if !isCommentNodeType(node.Type()) {
+			return true
+		}
+		if i, ok := byStart[node.StartByte()]; ok {
+			ranges[i].Facts.CommentedCode = detector.isCommentedCode(node)
+		}
+		return false
+	})
+}
+
+func (d *commentedCodeDetector) isCommentedCode(node *sitter.Node) bool {
+	first, last := commentBlockBounds(node, d.source)
+	if !d.blocks[first.StartByte()] {
+		d.blocks[first.StartByte()] = true
+		d.classifyBlock(first, last)
+	}
+	return d.verdicts[node.StartByte()]
+}
+
+func (d *commentedCodeDetector) classifyBlock(first, last *sitter.Node) {
+	if isTrailingComment(first, d.source) {
+		return
+	}
+
+	var nodes []*sitter.Node
+	var lines []string
+	for node := first; node != nil; node = node.NextNamedSibling() {
+		line, ok := stripLineCommentMarker(d.source[node.StartByte():node.EndByte()])
+		if !ok {
+			return
+		}
+		nodes = append(nodes, node)
+		lines = append(lines, line)
+		if node.Equal(last) {
+			break
+		}
+	}
+
+	for start := 0; start < len(lines); {
+		if !isCodeCandidate(d.lang, lines[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end+1 < len(lines) && isCodeCandidate(d.lang, lines[end+1]) {
+			end++
+		}
+
+		code := dedent(append([]string{}, lines[start:end+1]...))
+		if looksLikeCode(d.lang, code) {
+			for _, node := range nodes[start : end+1] {
+				d.verdicts[node.StartByte()] = true
+			}
+			d.runs = append(d.runs, commentedCodeRun{first: nodes[start], last: nodes[end], code: code})
+		}
+		start = end + 1
+	}
+}
+
+func stripLineCommentMarker(comment string) (string, bool) {
+	comment = strings.TrimRight(strings.TrimLeft(comment, " \t"), "\r\n")
+	for _, marker := range lineCommentMarkers {
+		if rest, found := strings.CutPrefix(comment, marker); found {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || width < indent {
+			indent = width
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func isCodeCandidate(lang *sitter.Language, line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return true
+	}
+	if proseLine.MatchString(line) {
+		return false
+	}
+	return strings.ContainsAny(line, codePunctuation) || parseErrors(lang, line+"\n") == 0
+}
+
+func looksLikeCode(lang *sitter.Language, text string) bool {
+	var lines, codeLines int
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines++
+		if strings.ContainsAny(line, codePunctuation) {
+			codeLines++
+		}
+	}
+	if lines == 0 || codeLines*2 < lines {
+		return false
+	}
+
+	named, errors := parseNodeCounts(lang, text)
+	return named > 1 && errors*10 <= named
+}
+
+func parseErrors(lang *sitter.Language, text string) int {
+	_, errors := parseNodeCounts(lang, text)
+	return errors
+}
+
+func parseNodeCounts(lang *sitter.Language, text string) (named, errors int) {
+	parser := parsers.Get(lang)
+	defer parsers.Put(lang, parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, []byte(text))
+	if err != nil || tree == nil {
+		return 0, 1
+	}
+	defer tree.Close()
+
+	Walk(tree.RootNode(), func(node *sitter.Node) bool {
+		if node.IsNamed() {
+			named++
+		}
+		if node.IsError() || node.IsMissing() {
+			errors++
+		}
+		return true
+	})
+	return named, errors
+}
+
+func (p *SingleLineCoreProcessor) CommentedCodeBlocks(source string) ([]CommentedCodeBlock, error) {
+	detector := newCommentedCodeDetector(p.lang, source)
+	keptLines := make(map[int]bool)
+	err := p.visitComments(source, func(node *sitter.Node, facts config.CommentFacts, removable bool) {
+		if !removable || p.directive(facts.Text) || keptByCommentConfigOverrides(p.commentConfig, facts) {
+			keptLines[int(node.StartPoint().Row)+1] = true
+			return
+		}
+		detector.isCommentedCode(node)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []CommentedCodeBlock
+	for _, run := range detector.runs {
+		block := CommentedCodeBlock{
+			StartLine: int(run.first.StartPoint().Row) + 1,
+			EndLine:   int(run.last.EndPoint().Row) + 1,
+			Code:      strings.TrimSpace(run.code),
+		}
+		if !blockHasKeptLine(block, keptLines) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func blockHasKeptLine(block CommentedCodeBlock, keptLines map[int]bool) bool {
+	for line := block.StartLine; line <= block.EndLine; line++ {
+		if keptLines[line] {
+			return true
+		}
+	}
+	return false
+}
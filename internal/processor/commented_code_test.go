@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"testing"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLooksLikeCode(t *testing.T) {
+	tests := []struct {
+		name string
+		lang *sitter.Language
+		text string
+		want bool
+	}{
+		{"go call", golang.GetLanguage(), "fmt.Println(x)\n", true},
+		{"go if block", golang.GetLanguage(), "if err != nil {\n\treturn err\n}\n", true},
+		{"go prose", golang.GetLanguage(), "this is a sentence about the code\n", false},
+		{"go bare return", golang.GetLanguage(), "return early\n", false},
+		{"javascript statements", javascript.GetLanguage(), "const x = compute(a, b);\nconsole.log(x);\n", true},
+		{"javascript prose with parens", javascript.GetLanguage(), "We retry (see the docs) when the API is flaky\n", false},
+		{"python assignment", python.GetLanguage(), "old_value = compute(settings)\n", true},
+		{"python label", python.GetLanguage(), "TODO: fix\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, looksLikeCode(tt.lang, tt.text))
+		})
+	}
+}
+
+func TestCommentedCodeBlocks(t *testing.T) {
+	input := `def main():
+    # Load settings before anything else touches the environment.
+    settings = load()
+    # old_value = compute(settings)
+    # if old_value > 3:
+    #     print(old_value)
+    # TODO: fix this later
+    run(settings)
+`
+	blocks, err := NewPythonSingleProcessor(true).CommentedCodeBlocks(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []CommentedCodeBlock{
+		{StartLine: 4, EndLine: 6, Code: "old_value = compute(settings)\nif old_value > 3:\n    print(old_value)"},
+	}, blocks)
+}
+
+func TestCommentedCodeBlocksHonorIgnorePatterns(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetCLIPatterns([]string{`^# fallback`}))
+
+	input := `def main():
+    settings = load()
+    # fallback = compute(settings)
+    # print(fallback)
+
+    # old_value = compute(settings)
+    run(settings)
+`
+	processor := NewPythonSingleProcessor(true)
+	processor.SetCommentConfig(cfg)
+	blocks, err := processor.CommentedCodeBlocks(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []CommentedCodeBlock{
+		{StartLine: 6, EndLine: 6, Code: "old_value = compute(settings)"},
+	}, blocks)
+}
+
+func TestCommentedCodeMode(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetMode(config.ModeCommentedCode, 0))
+
+	input := `function main() {
+  // const x = compute(a, b);
+  // console.log(x);
+  // We retry here because the API is flaky (see #12).
+  run();
+}
+`
+	expected := `function main() {
+  // We retry here because the API is flaky (see #12).
+  run();
+}
+`
+	processor := NewJavaScriptProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	selective, err := SelectivelyStripComments(input, "main.js", processor, map[int]bool{2: true, 3: true, 4: true}, true, cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, selective, "console.log")
+	assert.Contains(t, selective, "We retry here")
+}
@@ -17,6 +17,9 @@ type DirectiveMatcher func(line string) bool
 type CommentRange struct {
 	StartByte, EndByte uint32
 	Content            string
+	Facts              config.CommentFacts
+	Protected          string
+	Replacement        string
 }
 
 type BaseProcessor struct {
@@ -34,7 +37,91 @@ func (b *BaseProcessor) ShouldIgnoreComment(comment string) bool {
 	return b.commentConfig.ShouldIgnoreComment(comment)
 }
 
+var defaultCommentConfig = config.New()
+
+func keptByCommentConfig(cfg *config.Config, facts config.CommentFacts) bool {
+	kept, _ := explainCommentConfig(cfg, facts)
+	return kept
+}
+
+func keptByCommentConfigOverrides(cfg *config.Config, facts config.CommentFacts) bool {
+	kept, _, decided := explainCommentConfigOverrides(cfg, facts)
+	return decided && kept
+}
+
+func ExplainComment(cfg *config.Config, facts config.CommentFacts, preserveDirectives bool) (kept bool, reason string) {
+	if preserveDirectives && isLanguageDirective(facts.Language, facts.Text) {
+		return true, "directive"
+	}
+	if protected := protectedCommentText(facts.Text, facts.Language); protected != "" {
+		return true, protected
+	}
+	return explainCommentConfig(cfg, facts)
+}
+
+func explainCommentConfig(cfg *config.Config, facts config.CommentFacts) (kept bool, reason string) {
+	if kept, reason, decided := explainCommentConfigOverrides(cfg, facts); decided {
+		return kept, reason
+	}
+	if cfg == nil {
+		cfg = defaultCommentConfig
+	}
+	switch mode, threshold := cfg.Mode(); mode {
+	case config.ModeAIOnly:
+		score := NarrationScore(facts)
+		return score < threshold, fmt.Sprintf("AI narration score %.2f (threshold %.2f)", score, threshold)
+	case config.ModeRedundant:
+		score := RedundancyScore(facts)
+		return score < threshold, fmt.Sprintf("redundancy score %.2f (threshold %.2f)", score, threshold)
+	case config.ModeCommentedCode:
+		if facts.CommentedCode {
+			return false, "commented-out code"
+		}
+		return true, "not commented-out code"
+	}
+	if cfg.HasRemovePatterns() {
+		return false, "remove pattern" + firstPatternSource(cfg.MatchingRemovePatterns(facts.Text))
+	}
+	return false, "removed by default"
+}
+
+func explainCommentConfigOverrides(cfg *config.Config, facts config.CommentFacts) (kept bool, reason string, decided bool) {
+	switch facts.QueryCapture {
+	case config.QueryCaptureKeep:
+		return true, "captured as @keep by a query", true
+	case config.QueryCaptureRemove:
+		return false, "captured as @remove by a query", true
+	}
+	if cfg == nil {
+		cfg = defaultCommentConfig
+	}
+	if rule, ok := cfg.MatchRule(facts); ok {
+		return rule.Rule.Action == config.RuleActionKeep, fmt.Sprintf("rule %s (%s)", rule.Rule.Label(), rule.Source), true
+	}
+	if cfg.ShouldIgnoreComment(facts.Text) {
+		return true, "ignore pattern" + firstPatternSource(cfg.MatchingIgnorePatterns(facts.Text)), true
+	}
+	if marker, ok := cfg.MatchKeepMarker(facts.Text); ok && len(cfg.MatchingRemovePatterns(facts.Text)) == 0 {
+		return true, "task marker " + marker, true
+	}
+	if !cfg.IsRemovalCandidate(facts.Text) {
+		return true, "matches no remove pattern", true
+	}
+	return false, "", false
+}
+
+func firstPatternSource(matches []config.PatternSource) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %s (%s)", matches[0].Pattern, matches[0].Source)
+}
+
 func ParseCode(parser *sitter.Parser, source string) ([]CommentRange, error) {
+	return parseCodeWith(parser, source, nil)
+}
+
+func parseCodeWith(parser *sitter.Parser, source string, annotate func(root *sitter.Node, ranges []CommentRange) error) ([]CommentRange, error) {
 	sourceBytes := []byte(source)
 	tree, err := parser.ParseCtx(context.Background(), nil, sourceBytes)
 	if err != nil {
@@ -54,7 +141,13 @@ func ParseCode(parser *sitter.Parser, source string) ([]CommentRange, error) {
 		return nil, fmt.Errorf("syntax error in source code (rootNode.HasError() is true)")
 	}
 
-	return findCommentNodes(rootNode, source), nil
+	ranges := findCommentNodes(rootNode, source)
+	if annotate != nil {
+		if err := annotate(rootNode, ranges); err != nil {
+			return nil, err
+		}
+	}
+	return ranges, nil
 }
 
 func Walk(node *sitter.Node, callback func(*sitter.Node) bool) {
@@ -73,16 +166,12 @@ func parseCode(parser *sitter.Parser, source string) ([]CommentRange, error) {
 func findCommentNodes(node *sitter.Node, source string) []CommentRange {
 	var ranges []CommentRange
 
-	switch node.Type() {
-	case "comment",
-		"line_comment",
-		"block_comment",
-		"documentation_comment",
-		"doc_comment":
+	if isCommentNodeType(node.Type()) {
 		ranges = append(ranges, CommentRange{
 			StartByte: node.StartByte(),
 			EndByte:   node.EndByte(),
 			Content:   source[node.StartByte():node.EndByte()],
+			Facts:     describeComment(node, source),
 		})
 	}
 
@@ -104,7 +193,7 @@ func (b *BaseProcessor) filterCommentRanges(ranges []CommentRange) []CommentRang
 
 	var filteredRanges []CommentRange
 	for _, r := range ranges {
-		if !b.ShouldIgnoreComment(r.Content) {
+		if !keptByCommentConfig(b.commentConfig, commentFactsOf(r, "")) {
 			filteredRanges = append(filteredRanges, r)
 		}
 	}
@@ -138,7 +227,7 @@ func removeComments(source string, ranges []CommentRange) string {
 			continue
 		}
 
-		resultBytes = append(resultBytes[:start], resultBytes[end:]...)
+		resultBytes = append(resultBytes[:start], append([]byte(r.Replacement), resultBytes[end:]...)...)
 	}
 
 	return string(resultBytes)
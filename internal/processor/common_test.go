@@ -143,3 +143,112 @@ func TestPreserveOriginalTrailingNewline(t *testing.T) {
 		assert.Equal(t, "foo\nbar", result)
 	})
 }
+
+func TestRemovePatternsComposeWithIgnorePatterns(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"KEEP"}))
+	assert.NoError(t, cfg.SetCLIRemovePatterns([]string{`^//\s*(Here|Now|Step \d)`}))
+
+	input := `package main
+
+// Here we set up the server
+func main() {
+	// Now we start listening KEEP
+	// Step 2: serve
+	// Handles graceful shutdown on SIGTERM
+	listen() // Now call listen
+}
+`
+	expected := `package main
+
+func main() {
+	// Now we start listening KEEP
+	// Handles graceful shutdown on SIGTERM
+	listen()
+}
+`
+
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	base := BaseProcessor{commentConfig: cfg}
+	filtered := base.filterCommentRanges([]CommentRange{
+		{Content: "// Here we go"},
+		{Content: "// Here KEEP"},
+		{Content: "// Explains the invariant"},
+	})
+	assert.Equal(t, []CommentRange{{Content: "// Here we go"}}, filtered)
+}
+
+func TestTaskMarkersKeptByDefault(t *testing.T) {
+	input := `package main
+
+// TODO: handle errors
+func main() {
+	// FIXME
+	run() // HACK: retry twice
+	// start the loop
+}
+`
+	cfg := config.New()
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	for _, kept := range []string{"// TODO: handle errors", "// FIXME", "// HACK: retry twice"} {
+		assert.Contains(t, actual, kept)
+	}
+	assert.NotContains(t, actual, "start the loop")
+
+	allLines := map[int]bool{3: true, 5: true, 6: true, 7: true}
+	selective, err := SelectivelyStripComments(input, "main.go", processor, allLines, true, cfg)
+	assert.NoError(t, err)
+	assert.Contains(t, selective, "// TODO: handle errors")
+	assert.Contains(t, selective, "// HACK: retry twice")
+	assert.NotContains(t, selective, "start the loop")
+
+	python := NewPythonSingleProcessor(true)
+	python.SetCommentConfig(cfg)
+	pythonOutput, err := python.StripComments("x = 1  # TODO: tune\n# explain\ny = 2\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "x = 1  # TODO: tune\ny = 2\n", pythonOutput)
+
+	assert.NoError(t, cfg.SetNoDefaultKeeps(true))
+	stripped, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, stripped, "//")
+
+	only := config.New()
+	assert.NoError(t, only.SetCLIRemovePatterns([]string{"TODO"}))
+	processor.SetCommentConfig(only)
+	onlyTodo, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, onlyTodo, "TODO")
+	assert.Contains(t, onlyTodo, "// FIXME")
+}
+
+func TestExplainComment(t *testing.T) {
+	tests := []struct {
+		facts              config.CommentFacts
+		preserveDirectives bool
+		wantKept           bool
+		wantReason         string
+	}{
+		{config.CommentFacts{Text: "// eslint-disable-next-line no-console"}, true, true, "directive"},
+		{config.CommentFacts{Language: "go", Text: "//go:generate stringer"}, true, true, "directive"},
+		{config.CommentFacts{Language: "go", Text: "//go:generate stringer"}, false, false, "removed by default"},
+		{config.CommentFacts{Text: "/*! normalize.css | MIT License */"}, true, true, "license comment"},
+		{config.CommentFacts{Text: "// Copyright 2024 Example\n// Licensed under the Apache License"}, true, true, "license header"},
+		{config.CommentFacts{Text: "// TODO: fix this"}, true, true, "task marker TODO"},
+		{config.CommentFacts{Text: "// parse the config"}, true, false, "removed by default"},
+	}
+
+	for _, tt := range tests {
+		kept, reason := ExplainComment(nil, tt.facts, tt.preserveDirectives)
+		assert.Equal(t, tt.wantKept, kept, tt.facts.Text)
+		assert.Equal(t, tt.wantReason, reason, tt.facts.Text)
+	}
+}
@@ -26,16 +26,6 @@ func isCppDirective(line string) bool {
 	if strings.HasPrefix(trimmed, "//") {
 		content := strings.TrimSpace(strings.TrimPrefix(trimmed, "//"))
 
-		if strings.HasPrefix(content, "TODO") ||
-			strings.HasPrefix(content, "FIXME") ||
-			strings.HasPrefix(content, "NOTE") ||
-			strings.HasPrefix(content, "HACK") ||
-			strings.HasPrefix(content, "XXX") ||
-			strings.HasPrefix(content, "BUG") ||
-			strings.HasPrefix(content, "WARNING") {
-			return true
-		}
-
 		if strings.HasPrefix(content, "pragma") ||
 			strings.HasPrefix(content, "#pragma") {
 			return true
@@ -43,16 +43,16 @@ func TestIsCppDirective(t *testing.T) {
 		line     string
 		expected bool
 	}{
-		{"TODO", "// TODO: Fix this issue", true},
-		{"FIXME", "// FIXME: Memory leak", true},
-		{"NOTE", "// NOTE: This is important", true},
-		{"HACK", "// HACK: Temporary solution", true},
-		{"XXX", "// XXX: Review this code", true},
-		{"BUG", "// BUG: Known issue", true},
-		{"WARNING", "// WARNING: Deprecated", true},
+		{"TODO", "// TODO: Fix this issue", false},
+		{"FIXME", "// FIXME: Memory leak", false},
+		{"NOTE", "// NOTE: This is important", false},
+		{"HACK", "// HACK: Temporary solution", false},
+		{"XXX", "// XXX: Review this code", false},
+		{"BUG", "// BUG: Known issue", false},
+		{"WARNING", "// WARNING: Deprecated", false},
 		{"Pragma", "// pragma once", true},
 		{"PragmaHash", "// #pragma pack(1)", true},
-		{"SpacedTODO", "  // TODO: With spaces", true},
+		{"SpacedTODO", "  // TODO: With spaces", false},
 		{"RegularComment", "// This is a regular comment", false},
 		{"BlockComment", "/* This is a block comment */", false},
 		{"CodeLine", "int x = 5;", false},
@@ -1,43 +1,326 @@
 package processor
 
 import (
+	"context"
 	"fmt"
-	"nocmt/internal/config"
-	"regexp"
+	"sort"
 	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/css"
 )
 
-func isCSSDirective(line string) bool {
-	return false
+func isCSSDirective(comment string) bool {
+	return isSuppressionComment("css", comment)
 }
 
 type CSSProcessor struct {
+	language           string
+	lineComments       bool
 	preserveDirectives bool
+	commentConfig      *config.Config
+}
+
+type stylesheetCommentFinder interface {
+	commentRanges(source string) ([]CommentRange, error)
 }
 
 func NewCSSProcessor(preserveDirectives bool) *CSSProcessor {
-	return &CSSProcessor{preserveDirectives: preserveDirectives}
+	return &CSSProcessor{language: "css", preserveDirectives: preserveDirectives}
+}
+
+func NewSCSSProcessor(preserveDirectives bool) *CSSProcessor {
+	return &CSSProcessor{language: "scss", lineComments: true, preserveDirectives: preserveDirectives}
+}
+
+func NewLessProcessor(preserveDirectives bool) *CSSProcessor {
+	return &CSSProcessor{language: "less", lineComments: true, preserveDirectives: preserveDirectives}
 }
 
 func (p *CSSProcessor) StripComments(source string) (string, error) {
-	if strings.Contains(source, "/*") && !strings.Contains(source, "*/") {
-		return "", fmt.Errorf("syntax error: unterminated comment")
+	comments, err := p.commentRanges(source)
+	if err != nil {
+		return "", err
+	}
+
+	var rangesToRemove []CommentRange
+	for _, comment := range comments {
+		if _, remove, _ := p.commentDecision(comment); remove {
+			rangesToRemove = append(rangesToRemove, stylesheetRemovalRange(source, comment))
+		}
 	}
-	re := regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	if !re.MatchString(source) {
+	if len(rangesToRemove) == 0 {
 		return source, nil
 	}
-	stripped := re.ReplaceAllString(source, "")
-	cleaned := normalizeText(stripped)
+
+	cleaned := normalizeText(removeComments(source, rangesToRemove))
 	return PreserveOriginalTrailingNewline(source, cleaned), nil
 }
 
+func (p *CSSProcessor) InspectComments(source string) ([]CommentInfo, error) {
+	comments, err := p.commentRanges(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []CommentInfo
+	for _, comment := range comments {
+		startLine, endLine := FindCommentLineNumbers(source, comment)
+		directive, remove, reason := p.commentDecision(comment)
+		infos = append(infos, CommentInfo{
+			StartLine: startLine,
+			EndLine:   endLine,
+			Language:  p.language,
+			Kind:      commentKind(comment.Facts, directive),
+			Remove:    remove,
+			Reason:    reason,
+			Text:      strings.TrimRight(comment.Content, "\r\n"),
+		})
+	}
+	return infos, nil
+}
+
+func (p *CSSProcessor) commentDecision(comment CommentRange) (directive, remove bool, reason string) {
+	directive = isSuppressionComment(p.language, comment.Content)
+	switch {
+	case comment.Protected != "":
+		return directive, false, comment.Protected
+	case directive && p.preserveDirectives:
+		return directive, false, "directive"
+	case strings.HasPrefix(comment.Content, "///") && !p.commentConfig.RemovesDocComments():
+		return directive, false, fmt.Sprintf("doc comments are not removed in %s files without --remove-doc-comments", p.language)
+	}
+	remove, reason = inventoryDecision(p.commentConfig, comment.Facts, false)
+	return directive, remove, reason
+}
+
+func (p *CSSProcessor) commentRanges(source string) ([]CommentRange, error) {
+	var lines [][2]int
+	if p.lineComments {
+		var ok bool
+		if lines, ok = scanStylesheetLineComments(source); !ok {
+			return nil, p.parseError()
+		}
+	}
+
+	masked := []byte(source)
+	for _, line := range lines {
+		for i := line[0]; i < line[1]; i++ {
+			masked[i] = ' '
+		}
+	}
+
+	lang := css.GetLanguage()
+	parser := parsers.Get(lang)
+	defer parsers.Put(lang, parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, masked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source for %s: %w", p.language, err)
+	}
+	defer tree.Close()
+
+	root := tree.RootNode()
+	if !p.lineComments && root.HasError() {
+		return nil, p.parseError()
+	}
+	captures, err := queryCaptures(p.commentConfig, p.language, lang, root, string(masked))
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []CommentRange
+	Walk(root, func(node *sitter.Node) bool {
+		if node.Type() != "comment" {
+			return true
+		}
+		facts := describeComment(node, source)
+		facts.Language = p.language
+		facts.QueryCapture = captures[node.StartByte()]
+		comments = append(comments, CommentRange{
+			StartByte: node.StartByte(),
+			EndByte:   node.EndByte(),
+			Content:   source[node.StartByte():node.EndByte()],
+			Facts:     facts,
+			Protected: licenseOrAnnotationComment(node, source, p.language),
+		})
+		return false
+	})
+
+	for _, line := range lines {
+		if !isStylesheetGap(root, line[0], line[1]) {
+			return nil, p.parseError()
+		}
+		text := source[line[0]:line[1]]
+		protected := ""
+		if inLicenseHeader(source, line[0]) {
+			protected = "license header"
+		}
+		comments = append(comments, CommentRange{
+			StartByte: uint32(line[0]),
+			EndByte:   uint32(line[1]),
+			Content:   text,
+			Facts: config.CommentFacts{
+				Language: p.language,
+				Text:     text,
+				Trailing: !isOnlyWhitespaceBeforePosition(source, findLineStartBeforePosition(source, line[0]), line[0]),
+				Lines:    strings.Count(text, "\n") + 1,
+			},
+			Protected: protected,
+		})
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].StartByte < comments[j].StartByte
+	})
+	return comments, nil
+}
+
+var stylesheetValueNodeTypes = map[string]bool{
+	"comment":            true,
+	"string_value":       true,
+	"plain_value":        true,
+	"integer_value":      true,
+	"float_value":        true,
+	"color_value":        true,
+	"call_expression":    true,
+	"arguments":          true,
+	"attribute_selector": true,
+}
+
+func isStylesheetGap(root *sitter.Node, start, end int) bool {
+	node := root
+	for descended := true; descended; {
+		descended = false
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if int(child.StartByte()) <= start && end <= int(child.EndByte()) {
+				node, descended = child, true
+				break
+			}
+		}
+	}
+	if stylesheetValueNodeTypes[node.Type()] {
+		return false
+	}
+
+	gap := true
+	Walk(root, func(node *sitter.Node) bool {
+		if !gap || int(node.StartByte()) > start {
+			return false
+		}
+		if (node.IsError() || node.IsMissing()) && int(node.EndByte()) > start {
+			gap = false
+		}
+		return true
+	})
+	return gap
+}
+
+func (p *CSSProcessor) parseError() error {
+	return fmt.Errorf("tree-sitter parsing error for %s, comments not stripped", p.language)
+}
+
+func scanStylesheetLineComments(source string) ([][2]int, bool) {
+	var lines [][2]int
+	depth := 0
+	for i := 0; i < len(source); i++ {
+		switch {
+		case source[i] == '"' || source[i] == '\'':
+			i = endOfStylesheetString(source, i)
+		case source[i] == '(':
+			depth++
+		case source[i] == ')':
+			depth = max(depth-1, 0)
+		case source[i] == '{' || source[i] == '}' || source[i] == ';':
+			depth = 0
+		case source[i] == '[':
+			if end := strings.IndexAny(source[i:], "]\n"); end != -1 && source[i+end] == ']' {
+				i += end
+			}
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end == -1 {
+				return nil, false
+			}
+			i += end + 3
+		case strings.HasPrefix(source[i:], "//"):
+			if depth > 0 {
+				return nil, false
+			}
+			end := strings.IndexByte(source[i:], '\n')
+			if end == -1 {
+				end = len(source) - i
+			}
+			lines = append(lines, [2]int{i, i + len(strings.TrimRight(source[i:i+end], "\r"))})
+			i += end
+		case isUnquotedURL(source, i):
+			if end := strings.IndexByte(source[i:], ')'); end != -1 {
+				i += end
+			}
+		}
+	}
+	return lines, true
+}
+
+func endOfStylesheetString(source string, start int) int {
+	quote := source[start]
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i
+		}
+	}
+	return len(source)
+}
+
+func isUnquotedURL(source string, i int) bool {
+	if !strings.HasPrefix(strings.ToLower(source[i:min(i+4, len(source))]), "url(") {
+		return false
+	}
+	if i > 0 && (isIdentifierByte(source[i-1]) || source[i-1] == '-') {
+		return false
+	}
+	rest := strings.TrimLeft(source[i+4:], " \t\n")
+	return rest != "" && rest[0] != '"' && rest[0] != '\''
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func stylesheetRemovalRange(source string, comment CommentRange) CommentRange {
+	start, end := int(comment.StartByte), int(comment.EndByte)
+	lineStart := findLineStartBeforePosition(source, start)
+	lineEnd := end
+	for lineEnd < len(source) && (source[lineEnd] == ' ' || source[lineEnd] == '\t' || source[lineEnd] == '\r') {
+		lineEnd++
+	}
+
+	if isOnlyWhitespaceBeforePosition(source, lineStart, start) && (lineEnd == len(source) || source[lineEnd] == '\n') {
+		if lineEnd < len(source) {
+			lineEnd++
+		}
+		return CommentRange{StartByte: uint32(lineStart), EndByte: uint32(lineEnd)}
+	}
+	for start > lineStart && (source[start-1] == ' ' || source[start-1] == '\t') {
+		start--
+	}
+	return CommentRange{StartByte: uint32(start), EndByte: uint32(end)}
+}
+
 func (p *CSSProcessor) GetLanguageName() string {
-	return "css"
+	return p.language
 }
 
 func (p *CSSProcessor) PreserveDirectives() bool {
 	return p.preserveDirectives
 }
 
-func (p *CSSProcessor) SetCommentConfig(cfg *config.Config) {}
+func (p *CSSProcessor) SetCommentConfig(cfg *config.Config) {
+	p.commentConfig = cfg
+}
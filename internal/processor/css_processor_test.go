@@ -1,8 +1,11 @@
 package processor
 
 import (
+	"strings"
 	"testing"
 
+	"nocmt/internal/config"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,7 +39,7 @@ func TestCSSProcessor(t *testing.T) {
   /* This comment is not closed
 }`
 		_, err := processor.StripComments(input)
-		assert.Error(t, err)
+		assert.ErrorContains(t, err, "tree-sitter parsing error for css")
 	})
 
 	t.Run("PreserveDirectives", func(t *testing.T) {
@@ -63,3 +66,129 @@ func TestCSSProcessorPreserveDirectives(t *testing.T) {
 	processorWithoutDirectives := NewCSSProcessor(false)
 	assert.False(t, processorWithoutDirectives.PreserveDirectives())
 }
+
+func TestCSSProcessorHonorsConfig(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"TODO"}))
+
+	input := `/*! normalize.css v8 | MIT License */
+/* TODO: drop after the redesign */
+/* stylelint-disable declaration-no-important */
+.a { color: red !important; } /* why */
+.b { background: url(http://example.com/a.png); }
+`
+	expected := `/*! normalize.css v8 | MIT License */
+/* TODO: drop after the redesign */
+/* stylelint-disable declaration-no-important */
+.a { color: red !important; }
+.b { background: url(http://example.com/a.png); }
+`
+	processor := NewCSSProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	withoutDirectives, err := NewCSSProcessor(false).StripComments(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, withoutDirectives, "stylelint")
+	assert.Contains(t, withoutDirectives, "/*! normalize.css")
+}
+
+func TestSCSSProcessorLineComments(t *testing.T) {
+	input := `/// Primary brand color.
+$primary: #333; // brand
+// Mixins
+@mixin theme($color: $primary) {
+  color: $color; // inline
+  background: url(//cdn.example.com/bg.png);
+  content: "// not a comment";
+}
+.a {
+  /* nested */
+  &:hover { @include theme; }
+}
+`
+	expected := `/// Primary brand color.
+$primary: #333;
+@mixin theme($color: $primary) {
+  color: $color;
+  background: url(//cdn.example.com/bg.png);
+  content: "// not a comment";
+}
+.a {
+  &:hover { @include theme; }
+}
+`
+	actual, err := NewSCSSProcessor(true).StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	less := strings.ReplaceAll(strings.ReplaceAll(input, "$", "@"), "@mixin theme(@color: @primary)", ".theme(@color: @primary)")
+	lessOutput, err := NewLessProcessor(true).StripComments(less)
+	assert.NoError(t, err)
+	assert.NotContains(t, lessOutput, "// inline")
+	assert.Contains(t, lessOutput, `"// not a comment"`)
+
+	selective, err := SelectivelyStripComments(input, "theme.scss", NewSCSSProcessor(true), map[int]bool{2: true, 5: true}, true, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, selective, "// brand")
+	assert.NotContains(t, selective, "// inline")
+	assert.Contains(t, selective, "// Mixins")
+	assert.Contains(t, selective, "/* nested */")
+
+	comments, err := InspectComments(NewSCSSProcessor(true), input, nil)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 5)
+	assert.Equal(t, CommentKindDoc, comments[0].Kind)
+	assert.False(t, comments[0].Remove)
+}
+
+func TestStylesheetParseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor *CSSProcessor
+		input     string
+	}{
+		{"unterminated block comment", NewSCSSProcessor(true), "$x: 1;\n/* not closed\n.a { color: red; }\n"},
+		{"slashes inside url arguments", NewLessProcessor(true), "a { background: url(\"a.png\" //cdn); }\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.processor.StripComments(tt.input)
+			assert.ErrorContains(t, err, "tree-sitter parsing error")
+
+			_, err = tt.processor.InspectComments(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLessMixinsAndGuards(t *testing.T) {
+	input := `.mixin(@a) when (@a > 10) { // guard
+  width: @a; // width
+}
+.a {
+  .mixin(); // call
+  .mixin(20) !important;
+  a[href^=//cdn] { color: red; }
+}
+`
+	expected := `.mixin(@a) when (@a > 10) {
+  width: @a;
+}
+.a {
+  .mixin();
+  .mixin(20) !important;
+  a[href^=//cdn] { color: red; }
+}
+`
+	actual, err := NewLessProcessor(true).StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	scss, err := NewSCSSProcessor(true).StripComments("a[href^=//cdn] { color: red; } // link\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "a[href^=//cdn] { color: red; }\n", scss)
+}
@@ -1,7 +1,11 @@
 package processor
 
 import (
+	"regexp"
 	"strings"
+	"unicode"
+
+	"nocmt/internal/config"
 
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/golang"
@@ -24,6 +28,185 @@ func checkGoDirective(line string) bool {
 	return false
 }
 
+var goDirectiveMarkers = []string{"//export ", "//line ", "/*line ", "//nolint", "//lint:ignore ", "//lint:file-ignore "}
+
+var goExampleOutput = regexp.MustCompile(`(?i)^//\s*(unordered )?output:`)
+
+func goProtectedComment(node *sitter.Node, source string) string {
+	text := strings.TrimSpace(source[node.StartByte():node.EndByte()])
+	for _, marker := range goDirectiveMarkers {
+		if strings.HasPrefix(text, marker) || text == strings.TrimSpace(marker) {
+			return "Go directive " + strings.TrimSpace(marker)
+		}
+	}
+
+	first, last := commentBlockBounds(node, source)
+	if isDeprecationNotice(first, node, source) {
+		return "deprecation notice"
+	}
+	if precedesImportC(last, source) {
+		return "cgo preamble"
+	}
+	if isExampleOutput(first, node, last, source) {
+		return "Example output"
+	}
+	return ""
+}
+
+func isDeprecationNotice(first, node *sitter.Node, source string) bool {
+	notice := false
+	for comment := first; comment != nil; comment = comment.NextNamedSibling() {
+		body := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(source[comment.StartByte():comment.EndByte()]), "//"))
+		switch {
+		case strings.HasPrefix(body, "Deprecated:"):
+			notice = true
+		case body == "":
+			notice = false
+		}
+		if comment.Equal(node) {
+			return notice
+		}
+	}
+	return false
+}
+
+func precedesImportC(last *sitter.Node, source string) bool {
+	next := last.NextNamedSibling()
+	if next == nil || next.Type() != "import_declaration" || next.StartPoint().Row != last.EndPoint().Row+1 {
+		return false
+	}
+	spec := next.NamedChild(0)
+	if spec == nil || spec.Type() != "import_spec" {
+		return false
+	}
+	path := spec.ChildByFieldName("path")
+	return path != nil && source[path.StartByte():path.EndByte()] == `"C"`
+}
+
+func isExampleOutput(first, node, last *sitter.Node, source string) bool {
+	if last.NextNamedSibling() != nil {
+		return false
+	}
+	body := node.Parent()
+	if body == nil || body.Type() != "block" || body.Parent() == nil || body.Parent().Type() != "function_declaration" {
+		return false
+	}
+	name := body.Parent().ChildByFieldName("name")
+	if name == nil || !strings.HasPrefix(source[name.StartByte():name.EndByte()], "Example") {
+		return false
+	}
+
+	for comment := first; comment != nil; comment = comment.NextNamedSibling() {
+		if goExampleOutput.MatchString(strings.TrimSpace(source[comment.StartByte():comment.EndByte()])) {
+			return true
+		}
+		if comment.Equal(node) {
+			return false
+		}
+	}
+	return false
+}
+
+func isExportedName(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func goDocTarget(node *sitter.Node, source string) string {
+	if isTrailingComment(node, source) {
+		previous := node.PrevNamedSibling()
+		if previous != nil && previous.EndPoint().Row == node.StartPoint().Row && isExportedGoDeclaration(previous, source) {
+			return nodeName(previous, source)
+		}
+		return ""
+	}
+
+	_, last := commentBlockBounds(node, source)
+	next := last.NextNamedSibling()
+	if next == nil || next.StartPoint().Row != last.EndPoint().Row+1 {
+		return ""
+	}
+	if next.Type() == "package_clause" {
+		return "package"
+	}
+	if isExportedGoDeclaration(next, source) {
+		return nodeName(next, source)
+	}
+	return ""
+}
+
+func isExportedGoDeclaration(node *sitter.Node, source string) bool {
+	switch node.Type() {
+	case "function_declaration":
+		return isTopLevel(node) && isExportedName(nodeName(node, source))
+	case "method_declaration":
+		receiver := node.ChildByFieldName("receiver")
+		return isTopLevel(node) && isExportedName(nodeName(node, source)) && receiver != nil && isExportedName(receiverTypeName(receiver, source))
+	case "type_declaration", "const_declaration", "var_declaration":
+		if !isTopLevel(node) {
+			return false
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if spec := node.NamedChild(i); !isCommentNodeType(spec.Type()) && isExportedGoDeclaration(spec, source) {
+				return true
+			}
+		}
+		return false
+	case "type_spec", "type_alias", "const_spec", "var_spec":
+		return isTopLevel(node.Parent()) && hasExportedName(node, source)
+	case "field_declaration", "method_elem", "method_spec":
+		return hasExportedName(node, source) && isExportedGoType(node.Parent().Parent(), source)
+	}
+	return false
+}
+
+func isExportedGoType(node *sitter.Node, source string) bool {
+	for ; node != nil; node = node.Parent() {
+		if node.Type() == "type_spec" || node.Type() == "type_alias" {
+			return isExportedGoDeclaration(node, source)
+		}
+	}
+	return false
+}
+
+func isTopLevel(node *sitter.Node) bool {
+	return node != nil && node.Parent() != nil && node.Parent().Type() == "source_file"
+}
+
+func hasExportedName(node *sitter.Node, source string) bool {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) == "name" && isExportedName(source[node.Child(i).StartByte():node.Child(i).EndByte()]) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeName(node *sitter.Node, source string) string {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return source[name.StartByte():name.EndByte()]
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); hasExportedName(child, source) {
+			return nodeName(child, source)
+		}
+	}
+	return ""
+}
+
+func receiverTypeName(receiver *sitter.Node, source string) string {
+	name := ""
+	Walk(receiver, func(node *sitter.Node) bool {
+		if name == "" && node.Type() == "type_identifier" {
+			name = source[node.StartByte():node.EndByte()]
+		}
+		return name == ""
+	})
+	return name
+}
+
 func isGoSingleLineCommentNode(node *sitter.Node, sourceText string) bool {
 	if node.Type() == "comment" {
 		commentText := sourceText[node.StartByte():node.EndByte()]
@@ -49,7 +232,7 @@ func postProcessGoSingleLine(source string, preserveDirectives bool) (string, er
 	for i, line := range tempLines {
 		trimmed := strings.TrimSpace(line)
 		resultLines = append(resultLines, line)
-		if !preserveDirectives && strings.HasSuffix(trimmed, "{") && i+1 < len(tempLines) && strings.TrimSpace(tempLines[i+1]) != "" {
+		if !preserveDirectives && strings.HasSuffix(trimmed, "{") && !strings.HasPrefix(trimmed, "//") && i+1 < len(tempLines) && strings.TrimSpace(tempLines[i+1]) != "" {
 			resultLines = append(resultLines, "")
 		}
 	}
@@ -57,17 +240,43 @@ func postProcessGoSingleLine(source string, preserveDirectives bool) (string, er
 }
 
 func NewGoSingleProcessor(preserveDirectivesFlag bool) *GoSingleProcessor {
-	singleLineCore := NewSingleLineCoreProcessor(
+	processor := &GoSingleProcessor{}
+	processor.SingleLineCoreProcessor = NewSingleLineCoreProcessor(
 		"go",
 		golang.GetLanguage(),
-		isGoSingleLineCommentNode,
+		processor.isRemovableComment,
 		checkGoDirective,
 		postProcessGoSingleLine,
-	).WithPreserveDirectives(preserveDirectivesFlag).PreserveBlankRuns()
+	).WithPreserveDirectives(preserveDirectivesFlag).WithProtectedComments(processor.protectedComment).PreserveBlankRuns()
+	return processor
+}
 
-	return &GoSingleProcessor{
-		SingleLineCoreProcessor: singleLineCore,
+func (p *GoSingleProcessor) exportedDocsOnly() bool {
+	return p.commentConfig.DocsPolicy("go") == config.DocsExportedOnly
+}
+
+func (p *GoSingleProcessor) isRemovableComment(node *sitter.Node, sourceText string) bool {
+	if p.exportedDocsOnly() {
+		return node.Type() == "comment"
+	}
+	return isGoSingleLineCommentNode(node, sourceText)
+}
+
+func (p *GoSingleProcessor) protectedComment(node *sitter.Node, sourceText string) string {
+	if reason := goProtectedComment(node, sourceText); reason != "" {
+		return reason
+	}
+	if !p.exportedDocsOnly() {
+		return ""
+	}
+	switch target := goDocTarget(node, sourceText); target {
+	case "":
+	case "package":
+		return "package doc comment"
+	default:
+		return "doc comment on exported " + target
 	}
+	return ""
 }
 
 func NewGoProcessor(preserveDirectivesFlag bool) *GoSingleProcessor {
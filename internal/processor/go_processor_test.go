@@ -3,6 +3,8 @@ package processor
 import (
 	"testing"
 
+	"nocmt/internal/config"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,3 +75,159 @@ func TestIsGoDirective(t *testing.T) {
 		})
 	}
 }
+
+func TestGoProtectedComments(t *testing.T) {
+	input := `package main
+
+// #include <stdio.h>
+// static void hello() {
+//     printf("hi\n");
+// }
+import "C"
+
+import "fmt"
+
+// Old does things.
+//
+// Deprecated: Use New instead.
+func Old() {}
+
+//export Hello
+func Hello() {}
+
+//nolint:errcheck
+func run() {
+	//line generated.go:10
+	check() //lint:ignore SA1000 known issue
+}
+
+func ExampleHello() {
+	// say hi
+	fmt.Println("hi")
+	// Unordered output:
+	// hi
+}
+`
+	expected := `package main
+
+// #include <stdio.h>
+// static void hello() {
+//     printf("hi\n");
+// }
+import "C"
+
+import "fmt"
+
+// Deprecated: Use New instead.
+func Old() {}
+
+//export Hello
+func Hello() {}
+
+//nolint:errcheck
+func run() {
+	//line generated.go:10
+	check() //lint:ignore SA1000 known issue
+}
+
+func ExampleHello() {
+	fmt.Println("hi")
+	// Unordered output:
+	// hi
+}
+`
+	actual, err := NewGoProcessor(true).StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	withoutDirectives, err := NewGoProcessor(false).StripComments(input)
+	assert.NoError(t, err)
+	assert.Contains(t, withoutDirectives, "// static void hello() {\n//     printf(\"hi\\n\");\n// }\nimport \"C\"")
+	assert.Contains(t, withoutDirectives, "//nolint:errcheck\nfunc run() {")
+	assert.NotContains(t, withoutDirectives, "say hi")
+
+	allLines := make(map[int]bool)
+	for line := 1; line <= 31; line++ {
+		allLines[line] = true
+	}
+	processor := NewGoProcessor(false)
+	selective, err := SelectivelyStripComments(input, "main.go", processor, allLines, false, nil)
+	assert.NoError(t, err)
+	for _, kept := range []string{"#include", "printf", "Deprecated:", "//export", "//nolint", "//line", "//lint:ignore", "// Unordered output:", "// hi"} {
+		assert.Contains(t, selective, kept)
+	}
+	assert.NotContains(t, selective, "say hi")
+	assert.NotContains(t, selective, "Old does things")
+}
+
+func TestGoExportedOnlyDocs(t *testing.T) {
+	cfg := config.New()
+	cfg.Local.Docs = map[string]string{"go": config.DocsExportedOnly}
+	assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+	input := `// Package shop sells things.
+package shop
+
+// MaxItems limits a cart.
+const MaxItems = 10
+
+// Cart holds items.
+type Cart struct {
+	// Items are the products.
+	Items []string
+	owner string // owner is private
+}
+
+/* cart is internal */
+type cart struct{}
+
+// Add appends an item.
+func (c *Cart) Add(item string) {
+	// append it
+	c.Items = append(c.Items, item)
+}
+
+// helper is unexported.
+func helper() {}
+`
+	expected := `// Package shop sells things.
+package shop
+
+// MaxItems limits a cart.
+const MaxItems = 10
+
+// Cart holds items.
+type Cart struct {
+	// Items are the products.
+	Items []string
+	owner string
+}
+
+type cart struct{}
+
+// Add appends an item.
+func (c *Cart) Add(item string) {
+	c.Items = append(c.Items, item)
+}
+
+func helper() {}
+`
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	allLines := make(map[int]bool)
+	for line := 1; line <= 24; line++ {
+		allLines[line] = true
+	}
+	selective, err := SelectivelyStripComments(input, "shop.go", processor, allLines, true, cfg)
+	assert.NoError(t, err)
+	for _, kept := range []string{"Package shop", "MaxItems limits", "Cart holds", "Items are", "Add appends"} {
+		assert.Contains(t, selective, kept)
+	}
+	for _, removed := range []string{"owner is private", "cart is internal", "append it", "helper is unexported"} {
+		assert.NotContains(t, selective, removed)
+	}
+}
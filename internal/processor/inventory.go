@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const (
+	CommentKindLine      = "line"
+	CommentKindBlock     = "block"
+	CommentKindDoc       = "doc"
+	CommentKindDirective = "directive"
+)
+
+type CommentInfo struct {
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Language  string `json:"language"`
+	Kind      string `json:"kind"`
+	Remove    bool   `json:"remove"`
+	Reason    string `json:"reason"`
+	Text      string `json:"text"`
+}
+
+type CommentInspector interface {
+	InspectComments(source string) ([]CommentInfo, error)
+}
+
+func InspectComments(proc LanguageProcessor, source string, commentConfig *config.Config) ([]CommentInfo, error) {
+	if inspector, ok := proc.(CommentInspector); ok {
+		return inspector.InspectComments(source)
+	}
+
+	parser := GetParserForProcessor(proc)
+	if parser == nil {
+		return nil, fmt.Errorf("no tree-sitter parser available for language: %s", proc.GetLanguageName())
+	}
+	commentRanges, err := ParseCode(parser, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse code: %w", err)
+	}
+
+	var comments []CommentInfo
+	for _, comment := range commentRanges {
+		facts := commentFactsOf(comment, proc.GetLanguageName())
+		startLine, endLine := FindCommentLineNumbers(source, comment)
+		directive := IsDirective(proc, comment.Content)
+		info := CommentInfo{
+			StartLine: startLine,
+			EndLine:   endLine,
+			Language:  proc.GetLanguageName(),
+			Kind:      commentKind(facts, directive),
+			Text:      strings.TrimRight(comment.Content, "\r\n"),
+		}
+		if inLicenseHeader(source, int(comment.StartByte)) {
+			info.Reason = "license header"
+		} else {
+			info.Remove, info.Reason = inventoryDecision(commentConfig, facts, directive)
+		}
+		comments = append(comments, info)
+	}
+	return comments, nil
+}
+
+func (p *SingleLineCoreProcessor) InspectComments(source string) ([]CommentInfo, error) {
+	var comments []CommentInfo
+	err := p.visitComments(source, func(node *sitter.Node, facts config.CommentFacts, removable bool) {
+		directive := p.directive(facts.Text)
+		info := CommentInfo{
+			StartLine: int(node.StartPoint().Row) + 1,
+			EndLine:   int(node.EndPoint().Row) + 1,
+			Language:  p.langName,
+			Kind:      commentKind(facts, directive),
+			Text:      strings.TrimRight(facts.Text, "\r\n"),
+		}
+		if protected := p.protectedReason(node, source); protected != "" {
+			info.Reason = protected
+		} else if removable {
+			info.Remove, info.Reason = inventoryDecision(p.commentConfig, facts, directive && p.preserveDirectives)
+		} else {
+			info.Reason = fmt.Sprintf("%s comments are not removed in %s files", info.Kind, p.langName)
+			if info.Kind == CommentKindBlock || info.Kind == CommentKindDoc {
+				info.Reason += fmt.Sprintf(" without --remove-%s-comments", info.Kind)
+			}
+		}
+		comments = append(comments, info)
+	})
+	return comments, err
+}
+
+func inventoryDecision(cfg *config.Config, facts config.CommentFacts, preservedDirective bool) (remove bool, reason string) {
+	if preservedDirective {
+		return false, "directive"
+	}
+	kept, reason := explainCommentConfig(cfg, facts)
+	return !kept, reason
+}
+
+func commentKind(facts config.CommentFacts, directive bool) string {
+	text := strings.TrimSpace(facts.Text)
+	switch {
+	case directive:
+		return CommentKindDirective
+	case strings.HasPrefix(text, "///") || strings.HasPrefix(text, "//!") || strings.HasPrefix(text, "/*!"):
+		return CommentKindDoc
+	case strings.HasPrefix(text, "/**") && !strings.HasPrefix(text, "/**/"):
+		return CommentKindDoc
+	case facts.Language == "go" && facts.AboveDeclaration && !facts.Trailing:
+		return CommentKindDoc
+	case strings.HasPrefix(text, "/*") || strings.Contains(text, "\n"):
+		return CommentKindBlock
+	}
+	return CommentKindLine
+}
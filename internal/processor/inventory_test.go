@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"testing"
+
+	"nocmt/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectComments(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"TODO"}))
+
+	input := `package main
+
+//go:generate stringer -type=Kind
+
+// Run starts the server.
+func Run() {
+	/* block
+	   comment */
+	start() // TODO: retry
+}
+`
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	comments, err := processor.InspectComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []CommentInfo{
+		{StartLine: 3, EndLine: 3, Language: "go", Kind: CommentKindDirective, Reason: "directive", Text: "//go:generate stringer -type=Kind"},
+		{StartLine: 5, EndLine: 5, Language: "go", Kind: CommentKindDoc, Remove: true, Reason: "removed by default", Text: "// Run starts the server."},
+		{StartLine: 7, EndLine: 8, Language: "go", Kind: CommentKindBlock, Reason: "block comments are not removed in go files without --remove-block-comments", Text: "/* block\n\t   comment */"},
+		{StartLine: 9, EndLine: 9, Language: "go", Kind: CommentKindLine, Reason: "ignore pattern TODO (cli)", Text: "// TODO: retry"},
+	}, comments)
+}
+
+func TestInspectCommentsWithoutCoreProcessor(t *testing.T) {
+	input := `#!/bin/bash
+# shellcheck disable=SC2086
+# say hi
+echo hi
+`
+	comments, err := InspectComments(NewBashProcessor(true), input, nil)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, CommentKindDirective, comments[1].Kind)
+	assert.False(t, comments[1].Remove)
+	assert.Equal(t, CommentInfo{StartLine: 3, EndLine: 3, Language: "bash", Kind: CommentKindLine, Remove: true, Reason: "removed by default", Text: "# say hi"}, comments[2])
+}
@@ -3,6 +3,8 @@ package processor
 import (
 	"testing"
 
+	"nocmt/internal/config"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,3 +64,42 @@ func TestIsJSDirective(t *testing.T) {
 		})
 	}
 }
+
+func TestJavaScriptRemoveBlockAndDocComments(t *testing.T) {
+	cfg := config.New()
+	cfg.SetCommentKindRemoval(true, true)
+	assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+	input := `/*! lib v1.0 | MIT */
+/* Copyright (c) 2024 Example Corp */
+/**
+ * Adds numbers.
+ * @param {number} a
+ */
+function add(a, b) {
+  /* sum them */
+  return a + b;
+}
+/* @license Apache-2.0 */
+// SPDX-License-Identifier: MIT
+const x = /*#__PURE__*/ create();
+const page = import(/* webpackChunkName: "page" */ "./page");
+`
+	processor := NewJavaScriptProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Contains(t, actual, "/*! lib v1.0 | MIT */")
+	assert.Contains(t, actual, "/* Copyright (c) 2024 Example Corp */")
+	assert.Contains(t, actual, "/* @license Apache-2.0 */")
+	assert.Contains(t, actual, "/*#__PURE__*/")
+	assert.Contains(t, actual, `/* webpackChunkName: "page" */`)
+	assert.NotContains(t, actual, "Adds numbers")
+	assert.NotContains(t, actual, "sum them")
+
+	processor.SetCommentConfig(config.New())
+	kept, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Contains(t, kept, "Adds numbers")
+	assert.Contains(t, kept, "sum them")
+}
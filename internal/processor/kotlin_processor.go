@@ -11,17 +11,9 @@ type KotlinProcessor struct {
 	*SingleLineCoreProcessor
 }
 
-func isKotlinDirective(line string) bool {
-	trimmed := strings.TrimSpace(line)
-	
-	if strings.HasPrefix(trimmed, "//") {
-		return false
-	}
-	
-	
-	return strings.HasPrefix(trimmed, "@") ||
-		strings.HasPrefix(trimmed, "#!") ||
-		strings.Contains(trimmed, "@")
+func isKotlinDirective(comment string) bool {
+	trimmed := strings.TrimSpace(comment)
+	return strings.HasPrefix(trimmed, "#!") || isSuppressionComment("kotlin", trimmed)
 }
 
 func isKotlinSingleLineCommentNode(node *sitter.Node, sourceText string) bool {
@@ -38,14 +30,42 @@ func isKotlinSingleLineCommentNode(node *sitter.Node, sourceText string) bool {
 }
 
 func NewKotlinProcessor(preserveDirectives bool) *KotlinProcessor {
-	single := NewSingleLineCoreProcessor(
+	processor := &KotlinProcessor{}
+	processor.SingleLineCoreProcessor = NewSingleLineCoreProcessor(
 		"kotlin",
 		kotlin.GetLanguage(),
 		isKotlinSingleLineCommentNode,
 		isKotlinDirective,
 		nil,
-	).WithPreserveDirectives(preserveDirectives)
-	return &KotlinProcessor{SingleLineCoreProcessor: single}
+	).WithPreserveDirectives(preserveDirectives).WithProtectedComments(processor.protectedComment)
+	return processor
+}
+
+func (p *KotlinProcessor) protectedComment(node *sitter.Node, source string) string {
+	if !p.preserveDirectives {
+		return ""
+	}
+	start := int(node.StartByte())
+	trailing := !isOnlyWhitespaceBeforePosition(source, findLineStartBeforePosition(source, start), start)
+	if parent := node.Parent(); trailing && parent != nil && parent.Type() == "file_annotation" {
+		return "comment on a file annotation"
+	}
+	for last := node; ; {
+		next := last.NextSibling()
+		if parent := last.Parent(); next == nil && parent != nil && parent.Type() == "file_annotation" {
+			next = parent.NextSibling()
+		}
+		if next == nil || next.StartPoint().Row != lastRow(last, source)+1 {
+			return ""
+		}
+		if next.Type() == "file_annotation" {
+			return "comment on a file annotation"
+		}
+		if !isCommentNodeType(next.Type()) {
+			return ""
+		}
+		last = next
+	}
 }
 
 func (p *KotlinProcessor) GetLanguageName() string {
@@ -11,6 +11,10 @@ func TestKotlinProcessor_FileBased(t *testing.T) {
 		processor := NewKotlinProcessor(true)
 		RunFileBasedTestCaseNormalized(t, processor, "../../testdata/kotlin/original.kt", "../../testdata/kotlin/expected.kt")
 	})
+	t.Run("GradleKotlinScript", func(t *testing.T) {
+		processor := NewKotlinProcessor(true)
+		RunFileBasedTestCaseNormalized(t, processor, "../../testdata/kotlin/original.kts", "../../testdata/kotlin/expected.kts")
+	})
 	t.Run("WithoutDirectives_Simple", func(t *testing.T) {
 		processor := NewKotlinProcessor(false)
 		input := `// Regular comment
@@ -43,26 +47,18 @@ func TestIsKotlinDirective(t *testing.T) {
 		line     string
 		expected bool
 	}{
-		{"EntityAnnotation", "@Entity", true},
-		{"SuppressAnnotation", "@Suppress(\"UNCHECKED_CAST\")", true},
-		{"JvmStaticAnnotation", "@JvmStatic", true},
-		{"JvmFieldAnnotation", "@JvmField", true},
-		{"JvmNameAnnotation", "@JvmName(\"customName\")", true},
-		{"JvmOverloadsAnnotation", "@JvmOverloads", true},
-		{"DeprecatedAnnotation", "@Deprecated(\"Use new method\")", true},
-		{"TargetAnnotation", "@Target(AnnotationTarget.CLASS)", true},
-		{"RetentionAnnotation", "@Retention(AnnotationRetention.RUNTIME)", true},
-		{"ComponentAnnotation", "@Component", true},
-		{"ServiceAnnotation", "@Service", true},
-		{"RepositoryAnnotation", "@Repository", true},
-		{"ControllerAnnotation", "@Controller", true},
 		{"ShebangLine", "#!/usr/bin/env kotlin", true},
-		{"SpacedAnnotation", "  @Entity  ", true},
-		{"LineWithAnnotation", "class MyClass @Entity", true},
+		{"KtlintDisable", "// ktlint-disable no-wildcard-imports", true},
+		{"KtlintEnable", "// ktlint-enable", true},
+		{"DetektSuppression", "// detekt:disable MagicNumber", true},
+		{"NoInspection", "//noinspection GradleDependency", true},
+		{"Region", "// region Dependencies", true},
+		{"EndRegion", "//endregion", true},
+		{"FormatterOff", "// @formatter:off", true},
 		{"RegularLineComment", "// This is a comment", false},
+		{"RegionInProse", "// regional settings live here", false},
 		{"DocumentationComment", "/** This is a doc comment */", false},
 		{"BlockComment", "/* This is a block comment */", false},
-		{"CodeLine", "val x = 5", false},
 		{"EmptyLine", "", false},
 		{"CommentWithAtSymbol", "// @Entity in comment", false},
 	}
@@ -131,3 +127,30 @@ fun myFunction() {}`,
 		})
 	}
 }
+
+func TestKotlinFileAnnotationComments(t *testing.T) {
+	input := `// Generated accessors trip the inspection.
+@file:Suppress("UNUSED") // kept for reflection
+// The class name is part of the public API.
+@file:JvmName("Utils")
+// package comment
+package example
+
+val x = 1 // value
+`
+	expected := `// Generated accessors trip the inspection.
+@file:Suppress("UNUSED") // kept for reflection
+// The class name is part of the public API.
+@file:JvmName("Utils")
+package example
+
+val x = 1
+`
+	actual, err := NewKotlinProcessor(true).StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	withoutDirectives, err := NewKotlinProcessor(false).StripComments(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, withoutDirectives, "//")
+}
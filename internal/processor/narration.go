@@ -0,0 +1,179 @@
+package processor
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"nocmt/internal/config"
+)
+
+type NarrationSignal struct {
+	Name   string
+	Weight float64
+}
+
+var narrationOpeners = regexp.MustCompile(`(?i)^(here we|here's|here is|now we|now,|now let's|let's|let us|this function|this method|this code|this block|this line|this will|this is where|the following|we now|first,|first we|next,|next we|then,|then we|finally,|finally we|step \d+\s*[:.)-])`)
+
+var describingVerbs = regexp.MustCompile(`(?i)^(increment|decrement|initiali[sz]e|create|call|return|set|get|check if|check whether|loop (through|over)|iterate|add|append|update|define|declare|import|print|log)\b`)
+
+var firstPersonPlural = regexp.MustCompile(`(?i)\b(we|we're|we'll|we've|let's|us|our)\b`)
+
+var rationaleMarkers = regexp.MustCompile(`(?i)\b(why|because|since|otherwise|workaround|hack|bug|todo|fixme|xxx|note|warning|safety|invariant|see)\b|https?://|#\d+`)
+
+var commentMarkers = regexp.MustCompile(`^(//+!?|/\*+!?|#+!?|--|;+|\*+)\s*`)
+
+var operatorWords = []struct {
+	pattern *regexp.Regexp
+	words   []string
+}{
+	{regexp.MustCompile(`\+\+|\+=\s*1\b`), []string{"increment"}},
+	{regexp.MustCompile(`--|-=\s*1\b`), []string{"decrement"}},
+	{regexp.MustCompile(`\+=`), []string{"add"}},
+	{regexp.MustCompile(`-=`), []string{"subtract"}},
+	{regexp.MustCompile(`(^|[^=!<>+\-*/%&|^:])(:?=)([^=]|$)`), []string{"set", "assign"}},
+}
+
+var commentWordStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "for": true, "with": true, "by": true, "is": true, "it": true,
+	"this": true, "that": true, "we": true, "be": true, "from": true, "as": true, "at": true,
+	"into": true, "its": true, "our": true, "us": true, "then": true, "now": true, "here": true,
+}
+
+func NarrationScore(facts config.CommentFacts) float64 {
+	var score float64
+	for _, signal := range NarrationSignals(facts) {
+		score = 1 - (1-score)*(1-signal.Weight)
+	}
+	if rationaleMarkers.MatchString(commentBody(facts.Text)) {
+		score *= 0.4
+	}
+	return math.Round(score*100) / 100
+}
+
+func NarrationSignals(facts config.CommentFacts) []NarrationSignal {
+	body := commentBody(facts.Text)
+	if body == "" {
+		return nil
+	}
+
+	var signals []NarrationSignal
+	if narrationOpeners.MatchString(body) {
+		signals = append(signals, NarrationSignal{Name: "narration phrase", Weight: 0.7})
+	}
+	if describingVerbs.MatchString(body) {
+		signals = append(signals, NarrationSignal{Name: "describes the next step", Weight: 0.35})
+	}
+	if firstPersonPlural.MatchString(body) {
+		signals = append(signals, NarrationSignal{Name: "first-person plural", Weight: 0.3})
+	}
+	if containsEmoji(body) {
+		signals = append(signals, NarrationSignal{Name: "emoji", Weight: 0.4})
+	}
+	if overlap := restatementOverlap(body, facts.Code); overlap >= 0.5 {
+		signals = append(signals, NarrationSignal{Name: "restates the code", Weight: 0.7 * overlap})
+	}
+	return signals
+}
+
+func commentBody(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
+		line = commentMarkers.ReplaceAllString(line, "")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+func containsEmoji(text string) bool {
+	for _, r := range text {
+		if r >= 0x1F300 && r <= 0x1FAFF || r >= 0x2600 && r <= 0x27BF || r == 0x2705 || r == 0x274C {
+			return true
+		}
+	}
+	return false
+}
+
+func restatementOverlap(body, code string) float64 {
+	if code == "" {
+		return 0
+	}
+	commentWords := meaningfulWords(splitWords(body))
+	if len(commentWords) == 0 {
+		return 0
+	}
+
+	codeWords := make(map[string]bool)
+	for _, word := range splitWords(code) {
+		codeWords[stemWord(word)] = true
+	}
+	for _, operator := range operatorWords {
+		if operator.pattern.MatchString(code) {
+			for _, word := range operator.words {
+				codeWords[stemWord(word)] = true
+			}
+		}
+	}
+
+	matched := 0
+	for _, word := range commentWords {
+		if codeWords[stemWord(word)] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(commentWords))
+}
+
+func meaningfulWords(words []string) []string {
+	var result []string
+	for _, word := range words {
+		if !commentWordStopwords[word] {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+func splitWords(text string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+func stemWord(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s", "er"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"testing"
+
+	"nocmt/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNarrationScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		facts      config.CommentFacts
+		wantAbove  bool
+		wantSignal string
+	}{
+		{"narration phrase", config.CommentFacts{Text: "// Here we parse the config"}, true, "narration phrase"},
+		{"numbered step", config.CommentFacts{Text: "# Step 2: validate input"}, true, "narration phrase"},
+		{"emoji and first person", config.CommentFacts{Text: "// ✅ we are done 🚀"}, false, "emoji"},
+		{"restatement", config.CommentFacts{Text: "// count the active users", Code: "userCount := countActiveUsers(users)"}, true, "restates the code"},
+		{"operator restatement", config.CommentFacts{Text: "// increment i", Code: "i++"}, true, "restates the code"},
+		{"assignment restatement", config.CommentFacts{Text: "// set retries to 3", Code: "retries = 3"}, true, "restates the code"},
+		{"rationale is dampened", config.CommentFacts{Text: "// Now we retry because the API drops the first call"}, false, "narration phrase"},
+		{"plain explanation", config.CommentFacts{Text: "// The cache is shared across tenants", Code: "cache.Put(key, value)"}, false, ""},
+		{"block comment markers", config.CommentFacts{Text: "/*\n * This function builds the index\n */"}, true, "narration phrase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := NarrationScore(tt.facts)
+			assert.Equal(t, tt.wantAbove, score >= 0.7, "score %.2f", score)
+			if tt.wantSignal == "" {
+				assert.Empty(t, NarrationSignals(tt.facts))
+				return
+			}
+			var names []string
+			for _, signal := range NarrationSignals(tt.facts) {
+				names = append(names, signal.Name)
+			}
+			assert.Contains(t, names, tt.wantSignal)
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"parseHTTPResponse", []string{"parse", "http", "response"}},
+		{"user_count := 2", []string{"user", "count", "2"}},
+		{"MaxRetries", []string{"max", "retries"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, splitWords(tt.input), tt.input)
+	}
+}
+
+func TestAIOnlyMode(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetMode(config.ModeAIOnly, 0.7))
+
+	input := `package main
+
+func main() {
+	// Here we set up the server
+	server := newServer()
+	// Retries are capped so a bad upstream cannot stall deploys
+	server.Start()
+	// start the server
+	server.Start()
+}
+`
+	expected := `package main
+
+func main() {
+	server := newServer()
+	// Retries are capped so a bad upstream cannot stall deploys
+	server.Start()
+	server.Start()
+}
+`
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	selective, err := SelectivelyStripComments(input, "main.go", processor, map[int]bool{4: true, 6: true}, true, cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, selective, "Here we")
+	assert.Contains(t, selective, "// start the server")
+	assert.Contains(t, selective, "// Retries are capped")
+}
@@ -40,6 +40,8 @@ func NewProcessorFactory() *ProcessorFactory {
 	factory.Register(NewBashProcessor(false))
 	factory.Register(NewShellProcessor(false))
 	factory.Register(NewCSSProcessor(false))
+	factory.Register(NewSCSSProcessor(false))
+	factory.Register(NewLessProcessor(false))
 	factory.Register(NewKotlinProcessor(false))
 	factory.Register(NewJavaProcessor(false))
 	factory.Register(NewSwiftProcessor(false))
@@ -73,6 +75,12 @@ func NewProcessorFactory() *ProcessorFactory {
 	factory.RegisterConstructor("css", func(preserveDirectives bool) LanguageProcessor {
 		return NewCSSProcessor(preserveDirectives)
 	})
+	factory.RegisterConstructor("scss", func(preserveDirectives bool) LanguageProcessor {
+		return NewSCSSProcessor(preserveDirectives)
+	})
+	factory.RegisterConstructor("less", func(preserveDirectives bool) LanguageProcessor {
+		return NewLessProcessor(preserveDirectives)
+	})
 	factory.RegisterConstructor("kotlin", func(preserveDirectives bool) LanguageProcessor {
 		return NewKotlinProcessor(preserveDirectives)
 	})
@@ -146,8 +154,8 @@ func (f *ProcessorFactory) GetProcessorByExtension(filename string) (LanguagePro
 		".tcsh":  "shell",
 		".bat":   "shell",
 		".css":   "css",
-		".scss":  "css",
-		".less":  "css",
+		".scss":  "scss",
+		".less":  "less",
 		".kt":    "kotlin",
 		".kts":   "kotlin",
 		".java":  "java",
@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+var licenseMarkers = regexp.MustCompile(`@license\b|@preserve\b|SPDX-License-Identifier:`)
+
+var copyrightMarkers = regexp.MustCompile(`(?i)\bcopyright\b|\(c\)|©`)
+
+var licenseBoilerplate = regexp.MustCompile(`(?i)SPDX-License-Identifier:|\bcopyright\b|©|all rights reserved|licensed under|permission is hereby granted|\b(apache|mit|bsd|mozilla public|(gnu )?(lesser |affero )?general public) license\b|governed by a [\w-]+ license`)
+
+var bundlerAnnotation = regexp.MustCompile(`^/\*\s*[#@]__(PURE|NO_SIDE_EFFECTS|INLINE|NOINLINE|KEY)__\s*\*/$`)
+
+var magicComment = regexp.MustCompile(`^/\*\s*(webpack[A-Z]\w*\s*:|@vite-ignore)`)
+
+var fileHeaderPrefixes = map[string]bool{
+	"php_tag":        true,
+	"hash_bang_line": true,
+	"shebang":        true,
+}
+
+type commentProtector interface {
+	protectedReason(node *sitter.Node, source string) string
+}
+
+func annotateProtectedComments(proc LanguageProcessor, root *sitter.Node, source string, ranges []CommentRange) {
+	protector, ok := proc.(commentProtector)
+	if !ok {
+		return
+	}
+
+	byStart := make(map[uint32]int, len(ranges))
+	for i, r := range ranges {
+		byStart[r.StartByte] = i
+	}
+	Walk(root, func(node *sitter.Node) bool {
+		if !isCommentNodeType(node.Type()) {
+			return true
+		}
+		if i, ok := byStart[node.StartByte()]; ok {
+			ranges[i].Protected = protector.protectedReason(node, source)
+		}
+		return false
+	})
+}
+
+func licenseOrAnnotationComment(node *sitter.Node, source string, language string) string {
+	text := strings.TrimSpace(source[node.StartByte():node.EndByte()])
+	if !strings.HasPrefix(text, "/*") {
+		if inLicenseHeader(source, int(node.StartByte())) {
+			return "license header"
+		}
+		return ""
+	}
+
+	if reason := protectedBlockComment(text, language, isFileHeader(node)); reason != "" {
+		return reason
+	}
+	if magicComment.MatchString(text) && insideDynamicImport(node) {
+		return "magic comment"
+	}
+	return ""
+}
+
+func protectedBlockComment(text, language string, fileHeader bool) string {
+	switch {
+	case strings.HasPrefix(text, "/*!") && language != "rust":
+		return "license comment"
+	case licenseMarkers.MatchString(text):
+		return "license comment"
+	case copyrightMarkers.MatchString(text) && fileHeader:
+		return "copyright header"
+	case bundlerAnnotation.MatchString(text):
+		return "bundler annotation"
+	}
+	return ""
+}
+
+func protectedCommentText(text, language string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "/*") {
+		return protectedBlockComment(text, language, true)
+	}
+	if inLicenseHeader(text, 0) {
+		return "license header"
+	}
+	return ""
+}
+
+func inLicenseHeader(source string, start int) bool {
+	for _, marker := range []string{"//", "#"} {
+		if strings.HasPrefix(source[start:], marker) {
+			return start < licenseHeaderEnd(source, marker)
+		}
+	}
+	return false
+}
+
+func licenseHeaderEnd(source string, marker string) int {
+	var header strings.Builder
+	end := 0
+	first := true
+scan:
+	for offset := 0; offset < len(source); first = false {
+		lineEnd := len(source)
+		if next := strings.IndexByte(source[offset:], '\n'); next != -1 {
+			lineEnd = offset + next + 1
+		}
+		line := strings.TrimSpace(source[offset:lineEnd])
+		switch {
+		case first && (strings.HasPrefix(line, "#!") || strings.HasPrefix(line, "<?php")):
+		case strings.HasPrefix(line, marker):
+			header.WriteString(line + "\n")
+			end = lineEnd
+		case line == "" && header.Len() == 0:
+		default:
+			break scan
+		}
+		offset = lineEnd
+	}
+	if !licenseBoilerplate.MatchString(header.String()) {
+		return 0
+	}
+	return end
+}
+
+func isFileHeader(node *sitter.Node) bool {
+	if node.Parent() == nil || node.Parent().Parent() != nil {
+		return false
+	}
+	for previous := node.PrevSibling(); previous != nil; previous = previous.PrevSibling() {
+		if !isCommentNodeType(previous.Type()) && !fileHeaderPrefixes[previous.Type()] {
+			return false
+		}
+	}
+	return true
+}
+
+func insideDynamicImport(node *sitter.Node) bool {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() == "call_expression" {
+			function := parent.ChildByFieldName("function")
+			return function != nil && function.Type() == "import"
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicenseHeaderLineComments(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor LanguageProcessor
+		input     string
+		kept      []string
+		removed   []string
+	}{
+		{
+			name:      "Go BSD header above package doc",
+			processor: NewGoProcessor(true),
+			input:     "// Copyright 2009 The Go Authors. All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\n// Package demo does things.\npackage demo\n\nfunc run() {} // start\n",
+			kept:      []string{"// Copyright 2009 The Go Authors.", "// Use of this source code", "// license that can be found"},
+			removed:   []string{"// Package demo", "// start"},
+		},
+		{
+			name:      "Python Apache header after shebang",
+			processor: NewPythonSingleProcessor(true),
+			input:     "#!/usr/bin/env python3\n# Copyright 2024 Example Inc.\n#\n# Licensed under the Apache License, Version 2.0 (the \"License\");\n# you may not use this file except in compliance with the License.\n\n# load settings\nx = 1\n",
+			kept:      []string{"# Copyright 2024 Example Inc.", "#\n# Licensed under the Apache License", "# you may not use this file"},
+			removed:   []string{"# load settings"},
+		},
+		{
+			name:      "Rust SPDX line",
+			processor: NewRustProcessor(true),
+			input:     "// SPDX-License-Identifier: MIT OR Apache-2.0\n\nfn main() {\n    // call it\n    run();\n}\n",
+			kept:      []string{"// SPDX-License-Identifier: MIT OR Apache-2.0"},
+			removed:   []string{"// call it"},
+		},
+		{
+			name:      "JavaScript MIT boilerplate",
+			processor: NewJavaScriptProcessor(true),
+			input:     "// The MIT License (MIT)\n// Permission is hereby granted, free of charge, to any person obtaining a copy\n// of this software.\nconst a = 1; // one\n",
+			kept:      []string{"// The MIT License (MIT)", "// Permission is hereby granted", "// of this software."},
+			removed:   []string{"// one"},
+		},
+		{
+			name:      "Bash copyright header",
+			processor: NewBashProcessor(true),
+			input:     "#!/bin/bash\n# Copyright (c) 2023 Example\n# SPDX-License-Identifier: GPL-2.0-only\n\n# greet\necho hi\n",
+			kept:      []string{"# Copyright (c) 2023 Example", "# SPDX-License-Identifier: GPL-2.0-only"},
+			removed:   []string{"# greet"},
+		},
+		{
+			name:      "SCSS copyright header",
+			processor: NewSCSSProcessor(true),
+			input:     "// Copyright 2024 Example\n// Licensed under the MIT license\n\n// spacing\n.a { margin: 0; }\n",
+			kept:      []string{"// Copyright 2024 Example", "// Licensed under the MIT license"},
+			removed:   []string{"// spacing"},
+		},
+		{
+			name:      "Header without license text",
+			processor: NewGoProcessor(true),
+			input:     "// This file holds the demo helpers.\n// It is generated by hand.\npackage demo\n",
+			removed:   []string{"// This file holds", "// It is generated"},
+		},
+		{
+			name:      "License text after code",
+			processor: NewPythonSingleProcessor(true),
+			input:     "import os\n# Copyright 2024 Example\n# Licensed under MIT\nx = 1\n",
+			removed:   []string{"# Copyright 2024 Example", "# Licensed under MIT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.processor.StripComments(tt.input)
+			assert.NoError(t, err)
+			for _, kept := range tt.kept {
+				assert.Contains(t, actual, kept)
+			}
+			for _, removed := range tt.removed {
+				assert.NotContains(t, actual, removed)
+			}
+		})
+	}
+}
+
+func TestLicenseHeaderLineCommentsSelective(t *testing.T) {
+	input := "# Copyright (c) 2023 Example\n# SPDX-License-Identifier: GPL-2.0-only\n\n# greet\nx=1\n"
+	allLines := map[int]bool{1: true, 2: true, 4: true}
+
+	for _, processor := range []LanguageProcessor{NewBashProcessor(true), NewPythonSingleProcessor(true)} {
+		actual, err := SelectivelyStripComments(input, "script", processor, allLines, true, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, actual, "# Copyright (c) 2023 Example\n# SPDX-License-Identifier: GPL-2.0-only\n")
+		assert.NotContains(t, actual, "# greet")
+
+		comments, err := InspectComments(processor, input, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "license header", comments[0].Reason)
+		assert.False(t, comments[0].Remove)
+		assert.True(t, comments[len(comments)-1].Remove)
+	}
+}
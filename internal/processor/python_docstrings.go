@@ -0,0 +1,233 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type docstringEditor interface {
+	docstringEdits(source string, lines map[int]bool) ([]CommentRange, error)
+}
+
+var docstringFillerWords = map[string]bool{
+	"function": true, "method": true, "class": true, "helper": true, "given": true,
+	"value": true, "return": true, "object": true, "instance": true,
+}
+
+type pythonDocstring struct {
+	statement *sitter.Node
+	owner     *sitter.Node
+	only      bool
+}
+
+func (p *PythonSingleProcessor) StripComments(source string) (string, error) {
+	edits, err := p.docstringEdits(source, nil)
+	if err != nil {
+		return source, err
+	}
+	return p.SingleLineCoreProcessor.StripComments(removeComments(source, edits))
+}
+
+func (p *PythonSingleProcessor) docstringEdits(source string, lines map[int]bool) ([]CommentRange, error) {
+	policy := p.commentConfig.DocsPolicy("python")
+	if policy == config.DocsAll {
+		return nil, nil
+	}
+
+	parser := parsers.Get(p.lang)
+	defer parsers.Put(p.lang, parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, []byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source for %s: %w", p.langName, err)
+	}
+	defer tree.Close()
+	if tree.RootNode().HasError() {
+		return nil, fmt.Errorf("tree-sitter parsing error for %s, docstrings not changed", p.langName)
+	}
+
+	var edits []CommentRange
+	for _, docstring := range pythonDocstrings(tree.RootNode(), source) {
+		startLine := int(docstring.statement.StartPoint().Row) + 1
+		endLine := int(docstring.statement.EndPoint().Row) + 1
+		if lines != nil && !CommentOverlapsModifiedLines(startLine, endLine, lines) {
+			continue
+		}
+
+		switch policy {
+		case config.DocsRemovePrivate:
+			if docstring.owner != nil && isPrivatePythonName(pythonDefinitionName(docstring.owner, source)) {
+				edits = append(edits, removeDocstring(docstring, source))
+			}
+		case config.DocsRemoveRestating:
+			if docstring.owner != nil && restatesSignature(docstring, source) {
+				edits = append(edits, removeDocstring(docstring, source))
+			}
+		case config.DocsFirstLine:
+			if edit, ok := collapseDocstring(docstring, source); ok {
+				edits = append(edits, edit)
+			}
+		}
+	}
+	return edits, nil
+}
+
+func pythonDocstrings(root *sitter.Node, source string) []pythonDocstring {
+	var docstrings []pythonDocstring
+	if docstring, ok := firstDocstring(root, nil, source); ok {
+		docstrings = append(docstrings, docstring)
+	}
+	Walk(root, func(node *sitter.Node) bool {
+		if node.Type() == "function_definition" || node.Type() == "class_definition" {
+			if docstring, ok := firstDocstring(node.ChildByFieldName("body"), node, source); ok {
+				docstrings = append(docstrings, docstring)
+			}
+		}
+		return true
+	})
+	return docstrings
+}
+
+func firstDocstring(body, owner *sitter.Node, source string) (pythonDocstring, bool) {
+	if body == nil {
+		return pythonDocstring{}, false
+	}
+
+	statements := 0
+	var first *sitter.Node
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		child := body.NamedChild(i)
+		if isCommentNodeType(child.Type()) {
+			continue
+		}
+		if first == nil {
+			first = child
+		}
+		statements++
+	}
+	if first == nil || first.Type() != "expression_statement" || first.NamedChildCount() != 1 {
+		return pythonDocstring{}, false
+	}
+
+	str := first.NamedChild(0)
+	if str.Type() != "string" || strings.ContainsAny(stringPrefix(source[str.StartByte():str.EndByte()]), "fFbB") {
+		return pythonDocstring{}, false
+	}
+	return pythonDocstring{statement: first, owner: owner, only: statements == 1 && owner != nil}, true
+}
+
+func stringPrefix(literal string) string {
+	return literal[:max(strings.IndexAny(literal, `"'`), 0)]
+}
+
+func stringQuote(literal string) string {
+	rest := literal[len(stringPrefix(literal)):]
+	if strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`) {
+		return rest[:3]
+	}
+	return rest[:1]
+}
+
+func docstringText(literal string) string {
+	quote := stringQuote(literal)
+	body := strings.TrimPrefix(literal[len(stringPrefix(literal)):], quote)
+	return strings.TrimSuffix(body, quote)
+}
+
+func pythonDefinitionName(owner *sitter.Node, source string) string {
+	name := owner.ChildByFieldName("name")
+	if name == nil {
+		return ""
+	}
+	return source[name.StartByte():name.EndByte()]
+}
+
+func isPrivatePythonName(name string) bool {
+	return strings.HasPrefix(name, "_") && !(strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__"))
+}
+
+func restatesSignature(docstring pythonDocstring, source string) bool {
+	signature := pythonDefinitionName(docstring.owner, source)
+	for _, field := range []string{"parameters", "return_type", "superclasses"} {
+		if node := docstring.owner.ChildByFieldName(field); node != nil {
+			signature += " " + source[node.StartByte():node.EndByte()]
+		}
+	}
+
+	signatureWords := make(map[string]bool)
+	for _, word := range splitWords(signature) {
+		signatureWords[stemWord(word)] = true
+	}
+	str := docstring.statement.NamedChild(0)
+	for _, word := range meaningfulWords(splitWords(docstringText(source[str.StartByte():str.EndByte()]))) {
+		if !signatureWords[stemWord(word)] && !docstringFillerWords[word] && !docstringFillerWords[stemWord(word)] {
+			return false
+		}
+	}
+	return true
+}
+
+func removeDocstring(docstring pythonDocstring, source string) CommentRange {
+	start := int(docstring.statement.StartByte())
+	end := int(docstring.statement.EndByte())
+	lineStart := findLineStartBeforePosition(source, start)
+	lineEnd := skipSpacesAndTabs(source, end)
+	if lineEnd < len(source) && source[lineEnd] == ';' {
+		lineEnd = skipSpacesAndTabs(source, lineEnd+1)
+		end = lineEnd
+	}
+
+	if !isOnlyWhitespaceBeforePosition(source, lineStart, start) || (lineEnd < len(source) && source[lineEnd] != '\n') {
+		replacement := ""
+		if docstring.only {
+			replacement = "pass"
+		}
+		return CommentRange{StartByte: uint32(start), EndByte: uint32(end), Replacement: replacement}
+	}
+
+	if lineEnd < len(source) && source[lineEnd] == '\n' {
+		lineEnd++
+	}
+	replacement := ""
+	if docstring.only {
+		replacement = source[lineStart:start] + "pass\n"
+	}
+	return CommentRange{StartByte: uint32(lineStart), EndByte: uint32(lineEnd), Replacement: replacement}
+}
+
+func skipSpacesAndTabs(source string, pos int) int {
+	for pos < len(source) && (source[pos] == ' ' || source[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+func collapseDocstring(docstring pythonDocstring, source string) (CommentRange, bool) {
+	str := docstring.statement.NamedChild(0)
+	literal := source[str.StartByte():str.EndByte()]
+	text := docstringText(literal)
+	if !strings.Contains(text, "\n") {
+		return CommentRange{}, false
+	}
+
+	summary := ""
+	for _, line := range strings.Split(text, "\n") {
+		if summary = strings.TrimSpace(line); summary != "" {
+			break
+		}
+	}
+	quote := stringQuote(literal)
+	if strings.HasSuffix(summary, quote[:1]) {
+		summary += " "
+	}
+	return CommentRange{
+		StartByte:   str.StartByte(),
+		EndByte:     str.EndByte(),
+		Replacement: stringPrefix(literal) + quote + summary + quote,
+	}, true
+}
@@ -3,6 +3,8 @@ package processor
 import (
 	"testing"
 
+	"nocmt/internal/config"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +73,213 @@ func TestIsPythonDirective(t *testing.T) {
 		})
 	}
 }
+
+func TestPythonDocstringPolicy(t *testing.T) {
+	input := `"""Billing helpers.
+
+Long module description.
+"""
+
+TEMPLATE = """not a docstring
+spanning lines"""
+
+
+def _round(value):
+    """Round a value.
+
+    Args:
+        value: the value
+    """
+    return round(value)
+
+
+def _noop():
+    """Do nothing."""
+
+
+def get_invoice_total(invoice):
+    """Get the invoice total."""
+    return invoice.total
+
+
+def charge(card, amount):
+    """Charge the card, retrying once on network errors."""
+    return card.charge(amount)
+
+
+def _inline(): """Inline."""
+`
+	tests := []struct {
+		policy   string
+		expected string
+	}{
+		{config.DocsRemovePrivate, `"""Billing helpers.
+
+Long module description.
+"""
+
+TEMPLATE = """not a docstring
+spanning lines"""
+
+
+def _round(value):
+    return round(value)
+
+
+def _noop():
+    pass
+
+
+def get_invoice_total(invoice):
+    """Get the invoice total."""
+    return invoice.total
+
+
+def charge(card, amount):
+    """Charge the card, retrying once on network errors."""
+    return card.charge(amount)
+
+
+def _inline(): pass
+`},
+		{config.DocsRemoveRestating, `"""Billing helpers.
+
+Long module description.
+"""
+
+TEMPLATE = """not a docstring
+spanning lines"""
+
+
+def _round(value):
+    """Round a value.
+
+    Args:
+        value: the value
+    """
+    return round(value)
+
+
+def _noop():
+    """Do nothing."""
+
+
+def get_invoice_total(invoice):
+    return invoice.total
+
+
+def charge(card, amount):
+    """Charge the card, retrying once on network errors."""
+    return card.charge(amount)
+
+
+def _inline(): pass
+`},
+		{config.DocsFirstLine, `"""Billing helpers."""
+
+TEMPLATE = """not a docstring
+spanning lines"""
+
+
+def _round(value):
+    """Round a value."""
+    return round(value)
+
+
+def _noop():
+    """Do nothing."""
+
+
+def get_invoice_total(invoice):
+    """Get the invoice total."""
+    return invoice.total
+
+
+def charge(card, amount):
+    """Charge the card, retrying once on network errors."""
+    return card.charge(amount)
+
+
+def _inline(): """Inline."""
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			cfg := config.New()
+			cfg.Local.Docs = map[string]string{"python": tt.policy}
+			assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+			processor := NewPythonProcessor(true)
+			processor.SetCommentConfig(cfg)
+			actual, err := processor.StripComments(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestPythonDocstringSharingALine(t *testing.T) {
+	input := `def _f():
+    """Doc."""; x = 1
+    return x
+
+
+def _g(): """Doc.""" ; return 2
+
+
+def _h():
+    """Doc.""";
+`
+	expected := `def _f():
+    x = 1
+    return x
+
+
+def _g(): return 2
+
+
+def _h():
+    pass
+`
+	cfg := config.New()
+	cfg.Local.Docs = map[string]string{"python": config.DocsRemovePrivate}
+	assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+	processor := NewPythonProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestPythonDocstringPolicySelective(t *testing.T) {
+	cfg := config.New()
+	cfg.Local.Docs = map[string]string{"python": config.DocsRemovePrivate}
+	assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+	input := `def _old():
+    """Old helper."""
+    return 1
+
+
+def _new():
+    """New helper."""
+    # explain
+    return 2
+`
+	expected := `def _old():
+    """Old helper."""
+    return 1
+
+
+def _new():
+    ` + `
+    return 2
+`
+	processor := NewPythonProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := SelectivelyStripComments(input, "helpers.py", processor, map[int]bool{6: true, 7: true, 8: true, 9: true}, true, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
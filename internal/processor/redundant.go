@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"math"
+
+	"nocmt/internal/config"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+type RedundantComment struct {
+	Line     int
+	Text     string
+	Code     string
+	Coverage float64
+}
+
+type RedundancyLinter interface {
+	RedundantComments(source string, threshold float64) ([]RedundantComment, error)
+}
+
+func RedundancyScore(facts config.CommentFacts) float64 {
+	return math.Round(restatementOverlap(commentBody(facts.Text), facts.Code)*100) / 100
+}
+
+func (p *SingleLineCoreProcessor) RedundantComments(source string, threshold float64) ([]RedundantComment, error) {
+	var redundant []RedundantComment
+	err := p.visitComments(source, func(node *sitter.Node, facts config.CommentFacts, removable bool) {
+		if !removable || p.directive(facts.Text) {
+			return
+		}
+		if keptByCommentConfigOverrides(p.commentConfig, facts) {
+			return
+		}
+		if coverage := RedundancyScore(facts); coverage >= threshold && coverage > 0 {
+			redundant = append(redundant, RedundantComment{
+				Line:     int(node.StartPoint().Row) + 1,
+				Text:     facts.Text,
+				Code:     facts.Code,
+				Coverage: coverage,
+			})
+		}
+	})
+	return redundant, err
+}
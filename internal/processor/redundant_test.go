@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"testing"
+
+	"nocmt/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedundancyScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		facts config.CommentFacts
+		want  float64
+	}{
+		{"camel case call", config.CommentFacts{Text: "// get user by id", Code: "getUserByID(id)"}, 1},
+		{"snake case and stemming", config.CommentFacts{Text: "# loads the configs", Code: "load_config(path)"}, 1},
+		{"string literal", config.CommentFacts{Text: "// log started", Code: `log.Println("started")`}, 1},
+		{"partly covered", config.CommentFacts{Text: "// close the file before renaming it", Code: "f.Close()"}, 0.25},
+		{"no code", config.CommentFacts{Text: "// get user by id"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedundancyScore(tt.facts))
+		})
+	}
+}
+
+func TestRedundantComments(t *testing.T) {
+	input := `package main
+
+//go:generate stringer -type=Kind
+func main() {
+	// get user by id
+	user := getUserByID(id)
+	// Retries are capped to protect upstream
+	retry(user)
+	save(user) // save the user
+}
+`
+	comments, err := NewGoProcessor(true).RedundantComments(input, 0.7)
+	assert.NoError(t, err)
+	assert.Equal(t, []RedundantComment{
+		{Line: 5, Text: "// get user by id", Code: "user := getUserByID(id)", Coverage: 1},
+		{Line: 9, Text: "// save the user", Code: "save(user)", Coverage: 1},
+	}, comments)
+}
+
+func TestRedundantCommentsHonorCommentConfig(t *testing.T) {
+	cfg := config.New()
+	cfg.Local.Rules = []config.Rule{
+		{Name: "keep-trailing", Action: config.RuleActionKeep, Position: config.PositionTrailing},
+	}
+	assert.NoError(t, cfg.SetCLIPatterns([]string{"REVISIT"}))
+	assert.NoError(t, cfg.SetMode(config.ModeCommentedCode, 0))
+
+	input := `package main
+
+func main() {
+	// get user by id
+	user := getUserByID(id)
+	// REVISIT: load user by id
+	user = loadUserByID(id)
+	save(user) // save the user
+}
+`
+	processor := NewGoProcessor(true)
+	processor.SetCommentConfig(cfg)
+	comments, err := processor.RedundantComments(input, 0.7)
+	assert.NoError(t, err)
+	assert.Equal(t, []RedundantComment{
+		{Line: 4, Text: "// get user by id", Code: "user := getUserByID(id)", Coverage: 1},
+	}, comments)
+}
+
+func TestRedundantMode(t *testing.T) {
+	cfg := config.New()
+	assert.NoError(t, cfg.SetMode(config.ModeRedundant, 0.7))
+
+	input := `def main():
+    # load the config
+    config = load_config()
+    # Retries are capped to protect upstream
+    retry(config)
+`
+	expected := `def main():
+    config = load_config()
+    # Retries are capped to protect upstream
+    retry(config)
+`
+	processor := NewPythonSingleProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
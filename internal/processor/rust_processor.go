@@ -59,17 +59,117 @@ func isRustDirective(line string) bool {
 }
 
 func NewRustProcessor(preserveDirectivesFlag bool) *RustSingleProcessor {
-	singleLineCore := NewSingleLineCoreProcessor(
+	processor := &RustSingleProcessor{}
+	processor.SingleLineCoreProcessor = NewSingleLineCoreProcessor(
 		"rust",
 		rust.GetLanguage(),
 		isRustSingleLineCommentNode,
 		isRustDirective,
 		nil,
-	).WithPreserveDirectives(preserveDirectivesFlag).PreserveBlankRuns()
+	).WithPreserveDirectives(preserveDirectivesFlag).WithProtectedComments(processor.protectedComment).PreserveBlankRuns()
+	return processor
+}
+
+func (p *RustSingleProcessor) protectedComment(node *sitter.Node, source string) string {
+	marker := unsafeJustificationMarker(node, source, p.commentConfig.UnsafeMarkers())
+	if marker == "" {
+		return ""
+	}
+	return strings.TrimRight(marker, ": ") + " comment on unsafe code"
+}
+
+func unsafeJustificationMarker(node *sitter.Node, source string, markers []string) string {
+	marker := ""
+	for comment := node; comment != nil; comment = previousAdjacentComment(comment, source) {
+		if found := commentMarker(source[comment.StartByte():comment.EndByte()], markers); found != "" {
+			marker = found
+			break
+		}
+	}
+	if marker == "" {
+		return ""
+	}
+
+	last := node
+	for next := nextAdjacentSibling(last, source); next != nil; next = nextAdjacentSibling(last, source) {
+		if !isCommentNodeType(next.Type()) && next.Type() != "attribute_item" {
+			if isUnsafeItem(next) {
+				return marker
+			}
+			return ""
+		}
+		last = next
+	}
+	return ""
+}
+
+func commentMarker(comment string, markers []string) string {
+	text := strings.TrimSpace(strings.TrimLeft(comment, "/*!"))
+	for _, marker := range markers {
+		if len(text) >= len(marker) && strings.EqualFold(text[:len(marker)], marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+func previousAdjacentComment(node *sitter.Node, source string) *sitter.Node {
+	previous := node.PrevSibling()
+	if previous == nil || !isCommentNodeType(previous.Type()) || lastRow(previous, source)+1 != node.StartPoint().Row {
+		return nil
+	}
+	return previous
+}
+
+func nextAdjacentSibling(node *sitter.Node, source string) *sitter.Node {
+	next := node.NextSibling()
+	if next == nil || next.StartPoint().Row != lastRow(node, source)+1 {
+		return nil
+	}
+	return next
+}
+
+func lastRow(node *sitter.Node, source string) uint32 {
+	text := strings.TrimRight(source[node.StartByte():node.EndByte()], "\r\n")
+	return node.StartPoint().Row + uint32(strings.Count(text, "\n"))
+}
+
+func isUnsafeItem(node *sitter.Node) bool {
+	switch node.Type() {
+	case "unsafe_block":
+		return true
+	case "function_item", "function_signature_item":
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			if child.Type() == "function_modifiers" {
+				return hasChildOfType(child, "unsafe")
+			}
+		}
+		return false
+	case "impl_item", "trait_item":
+		return hasChildOfType(node, "unsafe")
+	}
 
-	return &RustSingleProcessor{
-		SingleLineCoreProcessor: singleLineCore,
+	found := false
+	Walk(node, func(child *sitter.Node) bool {
+		if found || child.StartPoint().Row != node.StartPoint().Row {
+			return false
+		}
+		if child.Type() == "unsafe_block" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func hasChildOfType(node *sitter.Node, nodeType string) bool {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.Child(i).Type() == nodeType {
+			return true
+		}
 	}
+	return false
 }
 
 func (p *RustSingleProcessor) GetLanguageName() string {
@@ -1,8 +1,11 @@
 package processor
 
 import (
+	"strings"
 	"testing"
 
+	"nocmt/internal/config"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +64,74 @@ func TestIsRustDirective(t *testing.T) {
 		})
 	}
 }
+
+func TestRustUnsafeJustifications(t *testing.T) {
+	input := `// SAFETY: the trait has no invariants.
+unsafe impl Send for Wrapper {}
+
+fn read(ptr: *const u8) -> u8 {
+    // SAFETY: ptr is valid for reads,
+    // checked by the caller.
+    let value = unsafe { *ptr };
+    // just a comment
+    let other = value + 1;
+    // invariant: len <= cap
+    unsafe {
+        grow();
+    }
+    // SAFETY: not followed by unsafe code
+    other
+}
+
+// SAFETY: callers uphold the contract.
+#[inline]
+pub unsafe fn raw() {}
+`
+	expected := `// SAFETY: the trait has no invariants.
+unsafe impl Send for Wrapper {}
+
+fn read(ptr: *const u8) -> u8 {
+    // SAFETY: ptr is valid for reads,
+    // checked by the caller.
+    let value = unsafe { *ptr };
+    let other = value + 1;
+    // invariant: len <= cap
+    unsafe {
+        grow();
+    }
+    other
+}
+
+// SAFETY: callers uphold the contract.
+#[inline]
+pub unsafe fn raw() {}
+`
+	cfg := config.New()
+	cfg.Local.UnsafeMarkers = []string{"invariant:"}
+	assert.NoError(t, cfg.SetCLIPatterns(nil))
+
+	processor := NewRustProcessor(true)
+	processor.SetCommentConfig(cfg)
+	actual, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	allLines := make(map[int]bool)
+	for i := 1; i <= strings.Count(input, "\n"); i++ {
+		allLines[i] = true
+	}
+	selective, err := SelectivelyStripComments(input, "lib.rs", processor, allLines, true, cfg)
+	assert.NoError(t, err)
+	for _, kept := range []string{"the trait has no invariants", "ptr is valid", "checked by the caller", "invariant:", "callers uphold"} {
+		assert.Contains(t, selective, kept)
+	}
+	for _, removed := range []string{"just a comment", "not followed by unsafe"} {
+		assert.NotContains(t, selective, removed)
+	}
+
+	processor.SetCommentConfig(config.New())
+	withoutMarker, err := processor.StripComments(input)
+	assert.NoError(t, err)
+	assert.NotContains(t, withoutMarker, "invariant:")
+	assert.Contains(t, withoutMarker, "// SAFETY: ptr is valid for reads,")
+}
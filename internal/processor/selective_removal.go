@@ -11,15 +11,28 @@ import (
 	"github.com/smacker/go-tree-sitter/csharp"
 	"github.com/smacker/go-tree-sitter/css"
 	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
 	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"github.com/smacker/go-tree-sitter/php"
 	"github.com/smacker/go-tree-sitter/python"
 	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/swift"
 	ts "github.com/smacker/go-tree-sitter/typescript/typescript"
 )
 
 func GetParserForProcessor(proc LanguageProcessor) *sitter.Parser {
+	language := languageForProcessor(proc)
+	if language == nil {
+		return nil
+	}
+
 	parser := sitter.NewParser()
+	parser.SetLanguage(language)
+	return parser
+}
 
+func languageForProcessor(proc LanguageProcessor) *sitter.Language {
 	var language *sitter.Language
 
 	switch proc.GetLanguageName() {
@@ -37,16 +50,23 @@ func GetParserForProcessor(proc LanguageProcessor) *sitter.Parser {
 		language = csharp.GetLanguage()
 	case "rust":
 		language = rust.GetLanguage()
-	case "css":
+	case "css", "scss", "less":
 		language = css.GetLanguage()
 	case "cpp":
 		language = cpp.GetLanguage()
+	case "java":
+		language = java.GetLanguage()
+	case "kotlin":
+		language = kotlin.GetLanguage()
+	case "swift":
+		language = swift.GetLanguage()
+	case "php":
+		language = php.GetLanguage()
 	default:
 		return nil
 	}
 
-	parser.SetLanguage(language)
-	return parser
+	return language
 }
 
 func ParseCodeForCommentRanges(parser *sitter.Parser, source string) ([]CommentRange, error) {
@@ -84,8 +104,25 @@ func IsDirective(proc LanguageProcessor, comment string) bool {
 	if !proc.PreserveDirectives() {
 		return false
 	}
+	return isLanguageDirective(proc.GetLanguageName(), comment)
+}
 
-	switch proc.GetLanguageName() {
+var directiveLanguages = []string{"bash", "cpp", "csharp", "css", "go", "java", "javascript", "kotlin", "php", "python", "rust", "swift", "typescript"}
+
+func isLanguageDirective(language, comment string) bool {
+	if language == "" {
+		for _, candidate := range directiveLanguages {
+			if isLanguageDirective(candidate, comment) {
+				return true
+			}
+		}
+		return false
+	}
+	if isSuppressionComment(language, comment) {
+		return true
+	}
+
+	switch language {
 	case "go":
 		return checkGoDirective(comment)
 	case "javascript":
@@ -100,7 +137,7 @@ func IsDirective(proc LanguageProcessor, comment string) bool {
 		return isCSharpDirective(comment)
 	case "rust":
 		return isRustDirectiveSelective(comment)
-	case "css":
+	case "css", "scss", "less":
 		return isCSSDirective(comment)
 	case "cpp":
 		return isCPPDirective(comment)
@@ -136,7 +173,7 @@ func FilterCommentsForRemoval(
 			continue
 		}
 
-		if commentConfig != nil && commentConfig.ShouldIgnoreComment(comment.Content) {
+		if comment.Protected != "" {
 			continue
 		}
 
@@ -144,6 +181,10 @@ func FilterCommentsForRemoval(
 			continue
 		}
 
+		if keptByCommentConfig(commentConfig, commentFactsOf(comment, proc.GetLanguageName())) {
+			continue
+		}
+
 		commentsToRemove = append(commentsToRemove, comment)
 	}
 
@@ -158,18 +199,44 @@ func SelectivelyStripComments(
 	preserveDirectives bool,
 	commentConfig *config.Config,
 ) (string, error) {
+	if finder, ok := proc.(stylesheetCommentFinder); ok {
+		commentRanges, err := finder.commentRanges(content)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse code: %w", err)
+		}
+		commentsToRemove := FilterCommentsForRemoval(commentRanges, content, modifiedLines, proc, preserveDirectives, commentConfig)
+		return RemoveComments(content, commentsToRemove), nil
+	}
+
 	parser := GetParserForProcessor(proc)
 	if parser == nil {
 		return "", fmt.Errorf("no tree-sitter parser available for language: %s. Ensure grammar is correctly configured", proc.GetLanguageName())
 	}
 
-	commentRanges, err := ParseCodeForCommentRanges(parser, content)
+	commentRanges, err := parseCodeWith(parser, content, func(root *sitter.Node, ranges []CommentRange) error {
+		captures, err := queryCaptures(commentConfig, proc.GetLanguageName(), languageForProcessor(proc), root, content)
+		if err != nil {
+			return err
+		}
+		annotateQueryCaptures(ranges, captures)
+		annotateCommentedCode(commentConfig, languageForProcessor(proc), root, content, ranges)
+		annotateProtectedComments(proc, root, content, ranges)
+		return nil
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to parse code: %w", err)
 	}
 
 	commentsToRemove := FilterCommentsForRemoval(commentRanges, content, modifiedLines, proc, preserveDirectives, commentConfig)
 
+	if editor, ok := proc.(docstringEditor); ok {
+		edits, err := editor.docstringEdits(content, modifiedLines)
+		if err != nil {
+			return "", err
+		}
+		commentsToRemove = append(commentsToRemove, edits...)
+	}
+
 	if len(commentsToRemove) == 0 {
 		return content, nil
 	}
@@ -200,16 +267,6 @@ func isCPPDirective(comment string) bool {
 	if strings.HasPrefix(trimmed, "//") {
 		content := strings.TrimSpace(strings.TrimPrefix(trimmed, "//"))
 
-		if strings.HasPrefix(content, "TODO") ||
-			strings.HasPrefix(content, "FIXME") ||
-			strings.HasPrefix(content, "NOTE") ||
-			strings.HasPrefix(content, "HACK") ||
-			strings.HasPrefix(content, "XXX") ||
-			strings.HasPrefix(content, "BUG") ||
-			strings.HasPrefix(content, "WARNING") {
-			return true
-		}
-
 		if strings.HasPrefix(content, "pragma") ||
 			strings.HasPrefix(content, "#pragma") {
 			return true
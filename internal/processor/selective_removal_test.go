@@ -244,6 +244,37 @@ func main() {
 	}
 }
 
+func TestFilterCommentsForRemovalWithRemovePatterns(t *testing.T) {
+	content := `package main
+
+// Here we print
+func main() {
+	// Explains why
+	fmt.Println("Hello") // Now print
+}
+`
+	commentConfig := config.New()
+	_ = commentConfig.SetCLIRemovePatterns([]string{`^//\s*(Here|Now)`})
+
+	comments := []CommentRange{
+		{StartByte: 14, EndByte: 30, Content: "// Here we print"},
+		{StartByte: 46, EndByte: 61, Content: "// Explains why"},
+		{StartByte: 84, EndByte: 96, Content: "// Now print"},
+	}
+	modifiedLines := map[int]bool{3: true, 5: true, 6: true}
+
+	commentsToRemove := FilterCommentsForRemoval(comments, content, modifiedLines, newMockProcessor("go", true), true, commentConfig)
+
+	if len(commentsToRemove) != 2 {
+		t.Fatalf("FilterCommentsForRemoval() returned %d comments, want 2", len(commentsToRemove))
+	}
+	for _, comment := range commentsToRemove {
+		if comment.Content == "// Explains why" {
+			t.Errorf("FilterCommentsForRemoval() removed a comment not matched by remove patterns")
+		}
+	}
+}
+
 func TestLanguageParsers(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/bash"
 )
 
@@ -26,6 +27,10 @@ func (p *ShellProcessor) PreserveDirectives() bool {
 	return p.preserveDirectives
 }
 
+func (p *ShellProcessor) protectedReason(node *sitter.Node, source string) string {
+	return licenseOrAnnotationComment(node, source, p.GetLanguageName())
+}
+
 func (p *ShellProcessor) StripComments(source string) (string, error) {
 	shebangRegex := regexp.MustCompile(`^(#!.*)$`)
 	lines := strings.Split(source, "\n")
@@ -51,6 +56,12 @@ func (p *ShellProcessor) StripComments(source string) (string, error) {
 		if p.preserveDirectives && p.isShellDirective(lines[lineIdx]) {
 			continue
 		}
+		if inLicenseHeader(source, int(r.StartByte)) {
+			continue
+		}
+		if keptByCommentConfig(p.commentConfig, commentFactsOf(r, p.GetLanguageName())) {
+			continue
+		}
 		filteredRanges = append(filteredRanges, r)
 	}
 
@@ -89,5 +100,5 @@ func (p *ShellProcessor) StripComments(source string) (string, error) {
 
 func (p *ShellProcessor) isShellDirective(line string) bool {
 	trimmed := strings.TrimSpace(line)
-	return strings.HasPrefix(trimmed, "# shellcheck")
+	return strings.HasPrefix(trimmed, "#") && isSuppressionComment("shell", trimmed)
 }
@@ -17,6 +17,7 @@ type SingleLineCoreProcessor struct {
 	preserveDirectives      bool
 	isDirective             func(string) bool
 	isSingleLineCommentNode func(node *sitter.Node, sourceText string) bool
+	protectedComment        func(node *sitter.Node, sourceText string) string
 	postProcess             func(source string, preserveDirectives bool) (string, error)
 	commentConfig           *config.Config
 	keepBlankRuns           bool
@@ -61,6 +62,35 @@ func (p *SingleLineCoreProcessor) PreserveBlankRuns() *SingleLineCoreProcessor {
 	return p
 }
 
+func (p *SingleLineCoreProcessor) WithProtectedComments(protected func(node *sitter.Node, sourceText string) string) *SingleLineCoreProcessor {
+	p.protectedComment = protected
+	return p
+}
+
+func (p *SingleLineCoreProcessor) protectedReason(node *sitter.Node, source string) string {
+	if reason := licenseOrAnnotationComment(node, source, p.langName); reason != "" {
+		return reason
+	}
+	if p.protectedComment == nil {
+		return ""
+	}
+	return p.protectedComment(node, source)
+}
+
+func (p *SingleLineCoreProcessor) directive(text string) bool {
+	return p.isDirective != nil && p.isDirective(text) || isSuppressionComment(p.langName, text)
+}
+
+func (p *SingleLineCoreProcessor) removesCommentKind(kind string) bool {
+	switch kind {
+	case CommentKindBlock:
+		return p.commentConfig.RemovesBlockComments()
+	case CommentKindDoc:
+		return p.commentConfig.RemovesDocComments()
+	}
+	return false
+}
+
 func findLineContainingBytePosition(targetBytePosition int, lineStartPositions []int, sourceCode string) int {
 	if len(lineStartPositions) == 0 {
 		return -1
@@ -129,6 +159,46 @@ func createCommentRangeForLine(
 	return createRangeForPartialLineComment(commentNode, lineContent, lineStartByte, commentPositionInLine)
 }
 
+func createCommentRangeForNode(
+	commentNode *sitter.Node,
+	lineIndex int,
+	sourceLines []string,
+	lineStartPositions []int,
+	fullSourceCode string,
+) CommentRange {
+	endByte := int(commentNode.EndByte())
+	endLineIndex := findLineContainingBytePosition(max(endByte-1, 0), lineStartPositions, fullSourceCode)
+	endLineEnd := lineStartPositions[endLineIndex] + len(sourceLines[endLineIndex])
+	codeFollows := endByte < endLineEnd && strings.TrimSpace(fullSourceCode[endByte:endLineEnd]) != ""
+
+	if endLineIndex == lineIndex && !codeFollows {
+		return createCommentRangeForLine(commentNode, lineIndex, sourceLines, lineStartPositions, fullSourceCode)
+	}
+
+	lineContent := sourceLines[lineIndex]
+	lineStartByte := lineStartPositions[lineIndex]
+	commentPositionInLine := int(commentNode.StartByte()) - lineStartByte
+
+	if !codeFollows && isCommentOnOtherwiseEmptyLine(lineContent, commentPositionInLine) {
+		commentRange := createRangeForFullLineComment(endLineIndex, sourceLines[endLineIndex], lineStartPositions[endLineIndex], sourceLines, fullSourceCode)
+		commentRange.StartByte = uint32(lineStartByte)
+		return commentRange
+	}
+
+	startByte := findStartOfWhitespaceBeforeComment(commentNode.StartByte(), lineContent, lineStartByte, commentPositionInLine)
+	if codeFollows {
+		startByte = commentNode.StartByte()
+		for endByte < endLineEnd && (fullSourceCode[endByte] == ' ' || fullSourceCode[endByte] == '\t') {
+			endByte++
+		}
+	}
+	return CommentRange{
+		StartByte: startByte,
+		EndByte:   uint32(endByte),
+		Content:   "",
+	}
+}
+
 func isCommentOnOtherwiseEmptyLine(lineContent string, commentStartPosition int) bool {
 	for i := range commentStartPosition {
 		if lineContent[i] != ' ' && lineContent[i] != '\t' {
@@ -213,9 +283,9 @@ func findStartOfWhitespaceBeforeComment(
 	return adjustedStart
 }
 
-func (p *SingleLineCoreProcessor) StripComments(source string) (string, error) {
+func (p *SingleLineCoreProcessor) visitComments(source string, visit func(node *sitter.Node, facts config.CommentFacts, removable bool)) error {
 	if p.lang == nil {
-		return source, fmt.Errorf("language %s not configured for Tree-sitter based comment removal", p.langName)
+		return fmt.Errorf("language %s not configured for Tree-sitter based comment removal", p.langName)
 	}
 
 	parser := parsers.Get(p.lang)
@@ -223,41 +293,74 @@ func (p *SingleLineCoreProcessor) StripComments(source string) (string, error) {
 
 	tree, err := parser.ParseCtx(context.Background(), nil, []byte(source))
 	if err != nil {
-		return source, fmt.Errorf("failed to parse source for %s: %w", p.langName, err)
+		return fmt.Errorf("failed to parse source for %s: %w", p.langName, err)
 	}
 	if tree == nil || tree.RootNode() == nil || tree.RootNode().HasError() {
-		return source, fmt.Errorf("tree-sitter parsing error for %s, comments not stripped", p.langName)
+		return fmt.Errorf("tree-sitter parsing error for %s, comments not stripped", p.langName)
 	}
 	defer tree.Close()
 
 	rootNode := tree.RootNode()
-	var rangesToModify []CommentRange
+	captures, err := queryCaptures(p.commentConfig, p.langName, p.lang, rootNode, source)
+	if err != nil {
+		return err
+	}
 
-	sourceLines := splitIntoLines(source)
-	lineStartPositions := calculateLinePositions(sourceLines)
+	detector := commentedCodeDetectorFor(p.commentConfig, p.lang, source)
 
 	Walk(rootNode, func(node *sitter.Node) bool {
-
-		if !p.isSingleLineCommentNode(node, source) {
+		isComment := isCommentNodeType(node.Type())
+		capture := ""
+		if isComment {
+			capture = captures[node.StartByte()]
+		}
+		removable := capture != "" || p.isSingleLineCommentNode(node, source)
+		if !removable && !isComment {
 			return true
 		}
 
-		commentContent := source[node.StartByte():node.EndByte()]
+		facts := describeComment(node, source)
+		facts.Language = p.langName
+		facts.QueryCapture = capture
+		if detector != nil {
+			facts.CommentedCode = detector.isCommentedCode(node)
+		}
+		if !removable && isComment && p.removesCommentKind(commentKind(facts, false)) {
+			removable = true
+		}
+		if removable && p.protectedReason(node, source) != "" {
+			removable = false
+		}
+		visit(node, facts, removable)
+		return false
+	})
+	return nil
+}
+
+func (p *SingleLineCoreProcessor) StripComments(source string) (string, error) {
+	var rangesToModify []CommentRange
 
-		// Check user-configured ignore patterns (e.g., "TODO", "FIXME")
-		if p.commentConfig != nil && p.commentConfig.ShouldIgnoreComment(commentContent) {
-			return true
+	sourceLines := splitIntoLines(source)
+	lineStartPositions := calculateLinePositions(sourceLines)
+
+	err := p.visitComments(source, func(node *sitter.Node, facts config.CommentFacts, removable bool) {
+		if !removable {
+			return
+		}
+		if p.preserveDirectives && p.directive(facts.Text) {
+			return
 		}
 
-		if p.preserveDirectives && p.isDirective != nil && p.isDirective(commentContent) {
-			return true
+		// Check user-configured rules and patterns (e.g., "TODO", "FIXME")
+		if keptByCommentConfig(p.commentConfig, facts) {
+			return
 		}
 
 		commentStartByte := int(node.StartByte())
 		commentLineIndex := findLineContainingBytePosition(commentStartByte, lineStartPositions, source)
 
 		if commentLineIndex != -1 {
-			commentRange := createCommentRangeForLine(
+			commentRange := createCommentRangeForNode(
 				node,
 				commentLineIndex,
 				sourceLines,
@@ -266,9 +369,10 @@ func (p *SingleLineCoreProcessor) StripComments(source string) (string, error) {
 			)
 			rangesToModify = append(rangesToModify, commentRange)
 		}
-
-		return false
 	})
+	if err != nil {
+		return source, err
+	}
 
 	cleaned := source
 
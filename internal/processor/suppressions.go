@@ -0,0 +1,116 @@
+package processor
+
+import "regexp"
+
+type suppressionComment struct {
+	tool    string
+	pattern *regexp.Regexp
+}
+
+func suppression(tool, pattern string) suppressionComment {
+	return suppressionComment{tool: tool, pattern: regexp.MustCompile(`^(?:` + pattern + `)`)}
+}
+
+var javaScriptSuppressions = []suppressionComment{
+	suppression("eslint", `eslint-(disable|enable)(-next-line|-line)?\b|eslint-env\s|eslint\s+[\w/@-]+\s*:`),
+	suppression("prettier", `prettier-ignore\b`),
+	suppression("istanbul", `istanbul\s+ignore\b`),
+	suppression("c8", `c8\s+ignore\b`),
+	suppression("v8", `v8\s+ignore\b`),
+	suppression("jshint", `jshint\s+\w+\s*:|jscs:(disable|enable)\b`),
+	suppression("flow", `\$Flow(FixMe|ExpectedError|Ignore)\b|@flow\b`),
+	suppression("biome", `biome-ignore\b`),
+}
+
+var typeScriptSuppressions = append([]suppressionComment{
+	suppression("typescript", `@ts-(ignore|expect-error|nocheck|check)\b`),
+	suppression("tslint", `tslint:(disable|enable)(-next-line|-line)?\b`),
+}, javaScriptSuppressions...)
+
+var cppSuppressions = []suppressionComment{
+	suppression("clang-tidy", `NOLINT(NEXTLINE|BEGIN|END)?\b`),
+	suppression("clang-format", `clang-format\s+(off|on)\b`),
+	suppression("cppcheck", `cppcheck-suppress\b`),
+	suppression("lcov", `LCOV_EXCL_(LINE|START|STOP|BR_LINE|BR_START|BR_STOP)\b`),
+	suppression("gcovr", `GCOVR_EXCL_(LINE|START|STOP)\b`),
+}
+
+var shellSuppressions = []suppressionComment{
+	suppression("shellcheck", `shellcheck\s+[a-z-]+=`),
+}
+
+var stylesheetSuppressions = []suppressionComment{
+	suppression("stylelint", `stylelint-(disable|enable)(-next-line|-line)?\b`),
+	suppression("prettier", `prettier-ignore\b`),
+	suppression("csslint", `csslint\s+[\w-]+\s*:`),
+	suppression("purgecss", `purgecss\s+(start\s+ignore|end\s+ignore|ignore(\s+current)?)\b`),
+	suppression("rtlcss", `rtl:(ignore|begin|end|raw|remove|rename)\b`),
+	suppression("autoprefixer", `autoprefixer:\s*(off|on|ignore\s+next)\b`),
+	suppression("source-map", `#\s*source(Mapping)?URL=`),
+}
+
+var suppressionCatalog = map[string][]suppressionComment{
+	"javascript": javaScriptSuppressions,
+	"typescript": typeScriptSuppressions,
+	"cpp":        cppSuppressions,
+	"css":        stylesheetSuppressions,
+	"scss":       stylesheetSuppressions,
+	"less":       stylesheetSuppressions,
+	"swift": {
+		suppression("swiftlint", `swiftlint:(disable|enable)\b`),
+		suppression("swiftformat", `swiftformat:(disable|enable|options|sort)\b`),
+		suppression("periphery", `periphery:ignore\b`),
+	},
+	"csharp": {
+		suppression("resharper", `ReSharper\s+(disable|restore)\b`),
+		suppression("dotcover", `dotCover\s+(disable|enable)\b`),
+	},
+	"php": {
+		suppression("phpcs", `phpcs:(ignoreFile|ignore|disable|enable)\b|@codingStandardsIgnore(Line|Start|End|File)\b`),
+		suppression("phpstan", `@phpstan-ignore(-next-line|-line)?\b`),
+		suppression("psalm", `@psalm-suppress\b`),
+		suppression("phan", `@phan-(suppress|file-suppress)\b`),
+		suppression("phpunit", `@codeCoverageIgnore(Start|End)?\b`),
+	},
+	"kotlin": {
+		suppression("ktlint", `ktlint-(disable|enable)\b`),
+		suppression("detekt", `detekt:`),
+		suppression("intellij", `noinspection\s`),
+		suppression("intellij-region", `(end)?region(\s|$)`),
+		suppression("intellij-formatter", `@formatter:(off|on)\b`),
+	},
+	"java": {
+		suppression("checkstyle", `CHECKSTYLE[:.]?\s*(OFF|ON)\b|NOCHECKSTYLE\b`),
+		suppression("sonar", `NOSONAR\b`),
+		suppression("intellij", `noinspection\s`),
+		suppression("intellij-formatter", `@formatter:(off|on)\b`),
+		suppression("spotless", `spotless:(off|on)\b`),
+		suppression("pmd", `NOPMD\b`),
+	},
+	"python": {
+		suppression("flake8", `noqa\b|flake8:\s*noqa\b`),
+		suppression("mypy", `type:\s*ignore\b`),
+		suppression("pyright", `pyright:\s*(ignore|basic|strict)\b`),
+		suppression("pylint", `pylint:\s*(disable|enable|skip-file)\b`),
+		suppression("coverage", `pragma:\s*no\s+(cover|branch)\b`),
+		suppression("black", `fmt:\s*(off|on|skip)\b`),
+		suppression("isort", `isort:\s*(skip_file|skip|off|on)\b`),
+		suppression("bandit", `nosec\b`),
+	},
+	"bash":  shellSuppressions,
+	"shell": shellSuppressions,
+}
+
+func suppressionTool(language, comment string) string {
+	body := commentBody(comment)
+	for _, entry := range suppressionCatalog[language] {
+		if entry.pattern.MatchString(body) {
+			return entry.tool
+		}
+	}
+	return ""
+}
+
+func isSuppressionComment(language, comment string) bool {
+	return suppressionTool(language, comment) != ""
+}
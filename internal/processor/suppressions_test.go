@@ -0,0 +1,91 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressionCatalogFixtures(t *testing.T) {
+	fixtures := map[string]struct {
+		file string
+		proc LanguageProcessor
+	}{
+		"javascript": {"javascript.js", NewJavaScriptProcessor(true)},
+		"typescript": {"typescript.ts", NewTypeScriptProcessor(true)},
+		"cpp":        {"cpp.cpp", NewCppProcessor(true)},
+		"css":        {"css.css", NewCSSProcessor(true)},
+		"scss":       {"scss.scss", NewSCSSProcessor(true)},
+		"less":       {"less.less", NewLessProcessor(true)},
+		"swift":      {"swift.swift", NewSwiftProcessor(true)},
+		"csharp":     {"csharp.cs", NewCSharpSingleProcessor(true)},
+		"php":        {"php.php", NewPHPProcessor(true)},
+		"java":       {"java.java", NewJavaProcessor(true)},
+		"kotlin":     {"kotlin.kt", NewKotlinProcessor(true)},
+		"python":     {"python.py", NewPythonSingleProcessor(true)},
+		"bash":       {"bash.sh", NewBashProcessor(true)},
+		"shell":      {"shell.zsh", NewShellProcessor(true)},
+	}
+
+	for language, entries := range suppressionCatalog {
+		t.Run(language, func(t *testing.T) {
+			fixture, ok := fixtures[language]
+			require.True(t, ok, "no fixture for %s", language)
+			content, err := os.ReadFile(filepath.Join("../../testdata/suppressions", fixture.file))
+			require.NoError(t, err)
+			source := string(content)
+
+			covered := make(map[string]bool)
+			var directives []string
+			for _, line := range strings.Split(source, "\n") {
+				if comment := suppressionInLine(language, line); comment != "" {
+					covered[suppressionTool(language, comment)] = true
+					directives = append(directives, comment)
+				}
+			}
+			for _, entry := range entries {
+				assert.True(t, covered[entry.tool], "fixture %s has no %s comment", fixture.file, entry.tool)
+			}
+
+			stripped, err := fixture.proc.StripComments(source)
+			require.NoError(t, err)
+			assert.NotContains(t, stripped, "remove me")
+			for _, comment := range directives {
+				assert.True(t, IsDirective(fixture.proc, comment), comment)
+				assert.Contains(t, stripped, comment)
+			}
+
+			if _, ok := fixture.proc.(*BashProcessor); ok {
+				return
+			}
+			if _, ok := fixture.proc.(*ShellProcessor); ok {
+				return
+			}
+			allLines := make(map[int]bool)
+			for line := 1; line <= strings.Count(source, "\n")+1; line++ {
+				allLines[line] = true
+			}
+			selective, err := SelectivelyStripComments(source, fixture.file, fixture.proc, allLines, true, nil)
+			require.NoError(t, err)
+			assert.NotContains(t, selective, "remove me")
+			for _, comment := range directives {
+				assert.Contains(t, selective, comment)
+			}
+		})
+	}
+}
+
+func suppressionInLine(language, line string) string {
+	for i := range line {
+		if strings.HasPrefix(line[i:], "//") || strings.HasPrefix(line[i:], "/*") || line[i] == '#' {
+			if comment := strings.TrimSpace(line[i:]); isSuppressionComment(language, comment) {
+				return comment
+			}
+		}
+	}
+	return ""
+}
@@ -45,11 +45,7 @@ func isSwiftDirective(line string) bool {
 		}
 	}
 
-	return strings.HasPrefix(trimmed, "// TODO:") ||
-		strings.HasPrefix(trimmed, "// FIXME:") ||
-		strings.HasPrefix(trimmed, "// MARK:") ||
-		strings.HasPrefix(trimmed, "// WARNING:") ||
-		strings.HasPrefix(trimmed, "// NOTE:") ||
+	return strings.HasPrefix(trimmed, "// MARK:") ||
 		strings.Contains(trimmed, "swiftlint:") ||
 		strings.Contains(trimmed, "sourcery:")
 }
@@ -45,13 +45,13 @@ func TestIsSwiftDirective(t *testing.T) {
 	}{
 		{"SwiftLintDirective", "// swiftlint:disable line_length", true},
 		{"SourceryDirective", "// sourcery: AutoMockable", true},
-		{"TODODirective", "// TODO: Implement this", true},
-		{"FIXMEDirective", "// FIXME: Fix this bug", true},
+		{"TODODirective", "// TODO: Implement this", false},
+		{"FIXMEDirective", "// FIXME: Fix this bug", false},
 		{"MARKDirective", "// MARK: - Section", true},
-		{"WARNINGDirective", "// WARNING: Deprecated", true},
-		{"NOTEDirective", "// NOTE: Important note", true},
+		{"WARNINGDirective", "// WARNING: Deprecated", false},
+		{"NOTEDirective", "// NOTE: Important note", false},
 		{"AttributeDirective", "// @available(iOS 13.0, *)", true},
-		{"SpacedDirective", "  // TODO: Fix this  ", true},
+		{"SpacedDirective", "  // TODO: Fix this  ", false},
 		{"InlineDirective", "func test() { // swiftlint:disable:next force_cast", true},
 		{"RegularLineComment", "// This is a comment", false},
 		{"DocumentationComment", "/// This is a doc comment", false},
@@ -0,0 +1,142 @@
+package walker
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var vendoredDirectories = map[string]bool{
+	"vendor":           true,
+	"third_party":      true,
+	"third-party":      true,
+	"node_modules":     true,
+	"bower_components": true,
+}
+
+var goGeneratedHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+var generatedAnnotation = regexp.MustCompile(`@generated\b`)
+
+var generatedBanners = []string{
+	"Generated by the protocol buffer compiler.  DO NOT EDIT!",
+	"Autogenerated by Thrift Compiler",
+	"This file was automatically generated by SWIG",
+	"<auto-generated>",
+	"<auto-generated/>",
+}
+
+var protobufOutput = regexp.MustCompile(`(\.pb\.(go|cc|h|swift|rs)|_pb2(_grpc)?\.pyi?|_pb\.(js|d\.ts)|_grpc_pb\.(js|d\.ts))$`)
+
+var minifiedName = regexp.MustCompile(`[.-]min\.(js|mjs|cjs|css)$`)
+
+var minifiableExtensions = map[string]bool{
+	".js":  true,
+	".mjs": true,
+	".cjs": true,
+	".css": true,
+}
+
+var commentPrefixes = []string{"//", "#", "/*", "*", "--", "<!--", ";"}
+
+const (
+	generatedHeaderLines     = 40
+	minifiedAverageLineWidth = 110
+)
+
+func generatedReason(relPath string, content []byte, attrs map[string]string) string {
+	switch {
+	case isSetAttribute(attrs["linguist-generated"]):
+		return "marked linguist-generated in .gitattributes"
+	case isSetAttribute(attrs["linguist-vendored"]):
+		return "marked linguist-vendored in .gitattributes"
+	}
+
+	if attrs["linguist-vendored"] != "false" {
+		if dir := vendoredDirectory(relPath); dir != "" {
+			return fmt.Sprintf("inside vendored directory %s/", dir)
+		}
+	}
+
+	if attrs["linguist-generated"] == "false" {
+		return ""
+	}
+	name := filepath.Base(relPath)
+	switch {
+	case protobufOutput.MatchString(name):
+		return "protocol buffer output"
+	case minifiedName.MatchString(name):
+		return "minified file"
+	}
+	if header := generatedHeaderLine(content); header != "" {
+		return fmt.Sprintf("generated file header %q", header)
+	}
+	if minifiableExtensions[filepath.Ext(name)] && averageLineWidth(content) > minifiedAverageLineWidth {
+		return "minified file"
+	}
+	return ""
+}
+
+func isSetAttribute(value string) bool {
+	return value != "" && value != "false"
+}
+
+func vendoredDirectory(relPath string) string {
+	parts := strings.Split(relPath, "/")
+	for _, part := range parts[:len(parts)-1] {
+		if vendoredDirectories[part] {
+			return part
+		}
+	}
+	return ""
+}
+
+func generatedHeaderLine(content []byte) string {
+	lines := strings.SplitN(string(content), "\n", generatedHeaderLines+1)
+	for i, line := range lines {
+		if i == generatedHeaderLines {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !isCommentLine(line) {
+			break
+		}
+		if isGeneratedHeader(line) {
+			return line
+		}
+	}
+	return ""
+}
+
+func isGeneratedHeader(line string) bool {
+	if goGeneratedHeader.MatchString(line) || generatedAnnotation.MatchString(line) {
+		return true
+	}
+	for _, banner := range generatedBanners {
+		if strings.Contains(line, banner) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCommentLine(line string) bool {
+	for _, prefix := range commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func averageLineWidth(content []byte) int {
+	if len(content) == 0 {
+		return 0
+	}
+	lines := strings.Count(strings.TrimRight(string(content), "\n"), "\n") + 1
+	return len(content) / lines
+}
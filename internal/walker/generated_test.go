@@ -0,0 +1,124 @@
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", name, err)
+		}
+	}
+}
+
+func TestGeneratedFileSkipReasons(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		".gitattributes":             "gen/** linguist-generated\nassets/** linguist-vendored=true\nvendor/patched/** -linguist-vendored\nhandwritten.pb.go -linguist-generated\n",
+		"main.go":                    "package main\n\n// Run starts the server.\nfunc Run() {}\n",
+		"gen/models.go":              "package gen\n",
+		"assets/lib.js":              "var a = 1;\n",
+		"vendor/github.com/x/y/y.go": "package y\n",
+		"third_party/lib/lib.py":     "x = 1\n",
+		"vendor/patched/fix.go":      "package patched\n",
+		"api/service.pb.go":          "package api\n",
+		"api/service_pb2.py":         "x = 1\n",
+		"handwritten.pb.go":          "package main\n",
+		"zz_generated.go":            "// Copyright 2024 Example\n\n// Code generated by controller-gen. DO NOT EDIT.\n\npackage api\n",
+		"schema.ts":                  "/**\n * @generated SignedSource<<abc>>\n */\nexport type A = string;\n",
+		"messages.py":                "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\nx = 1\n",
+		"strings.go":                 "package main\n\nconst warning = \"DO NOT EDIT\"\n",
+		"registry.go":                "// Do not edit this map without updating the docs.\npackage main\n",
+		"settings.py":                "# values are auto-generated at runtime\nx = 1\n",
+		"late.go":                    "package main\n\n// Code generated by tool. DO NOT EDIT.\n",
+		"late.ts":                    "export type A = string;\n/** @generated */\n",
+		"Reference.cs":               "// <auto-generated>\n//     This code was generated by a tool.\n// </auto-generated>\nclass A {}\n",
+		"app.min.js":                 "var a=1;\n",
+		"bundle.js":                  strings.Repeat("var a=function(){return 1};", 20) + "\n",
+		"style.css":                  ".a {\n  color: red;\n}\n",
+	}
+	writeTestFiles(t, tempDir, files)
+
+	policy := NewFilePolicy(tempDir, false)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", ""},
+		{"gen/models.go", "marked linguist-generated in .gitattributes"},
+		{"assets/lib.js", "marked linguist-vendored in .gitattributes"},
+		{"vendor/github.com/x/y/y.go", "inside vendored directory vendor/"},
+		{"third_party/lib/lib.py", "inside vendored directory third_party/"},
+		{"vendor/patched/fix.go", ""},
+		{"api/service.pb.go", "protocol buffer output"},
+		{"api/service_pb2.py", "protocol buffer output"},
+		{"handwritten.pb.go", ""},
+		{"zz_generated.go", `generated file header "// Code generated by controller-gen. DO NOT EDIT."`},
+		{"schema.ts", `generated file header "* @generated SignedSource<<abc>>"`},
+		{"messages.py", `generated file header "# Generated by the protocol buffer compiler.  DO NOT EDIT!"`},
+		{"strings.go", ""},
+		{"registry.go", ""},
+		{"settings.py", ""},
+		{"late.go", ""},
+		{"late.ts", ""},
+		{"Reference.cs", `generated file header "// <auto-generated>"`},
+		{"app.min.js", "minified file"},
+		{"bundle.js", "minified file"},
+		{"style.css", ""},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(tempDir, tt.path)
+		got, err := policy.SkipReason(path, []byte(files[tt.path]), false)
+		if err != nil {
+			t.Fatalf("SkipReason(%s) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("SkipReason(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestProcessRepositorySkipsGeneratedFiles(t *testing.T) {
+	for _, includeGenerated := range []bool{false, true} {
+		tempDir := t.TempDir()
+		files := map[string]string{
+			"main.go":           "package main\n\n// Run starts the server.\nfunc Run() {}\n",
+			"zz_generated.go":   "// Code generated by tool. DO NOT EDIT.\n\npackage main\n\n// Build builds.\nfunc Build() {}\n",
+			"vendor/lib/lib.go": "package lib\n\n// Lib helps.\nfunc Lib() {}\n",
+		}
+		writeTestFiles(t, tempDir, files)
+
+		integration := NewProcessorIntegration(ProcessorConfig{PreserveDirectives: true, IncludeGenerated: includeGenerated})
+		if err := integration.ProcessRepository(tempDir); err != nil {
+			t.Fatalf("ProcessRepository() error = %v", err)
+		}
+
+		for _, name := range []string{"zz_generated.go", "vendor/lib/lib.go"} {
+			content, err := os.ReadFile(filepath.Join(tempDir, name))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			if changed := string(content) != files[name]; changed != includeGenerated {
+				t.Errorf("IncludeGenerated=%v: %s changed = %v", includeGenerated, name, changed)
+			}
+		}
+
+		content, err := os.ReadFile(filepath.Join(tempDir, "main.go"))
+		if err != nil {
+			t.Fatalf("Failed to read main.go: %v", err)
+		}
+		if strings.Contains(string(content), "// Run starts the server.") {
+			t.Errorf("IncludeGenerated=%v: main.go should be processed", includeGenerated)
+		}
+	}
+}
@@ -22,6 +22,20 @@ func IsGitRepository(dir string) bool {
 	return IsGitRepository(parentDir)
 }
 
+func RepositoryRoot(dir string) string {
+	gitDir := filepath.Join(dir, ".git")
+	if _, err := os.Stat(gitDir); err == nil {
+		return dir
+	}
+
+	parentDir := filepath.Dir(dir)
+	if parentDir == dir {
+		return ""
+	}
+
+	return RepositoryRoot(parentDir)
+}
+
 func confirmNonGitUsage(input string) bool {
 	if input != "" {
 		scanner := bufio.NewScanner(strings.NewReader(input))
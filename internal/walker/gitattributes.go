@@ -0,0 +1,273 @@
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type attributeRule struct {
+	matcher *regexp.Regexp
+	attrs   []string
+}
+
+var builtinMacros = map[string][]string{
+	"binary": {"-diff", "-merge", "-text"},
+}
+
+const maxMacroDepth = 8
+
+type GitAttributes struct {
+	rootPath string
+	files    map[string][]attributeRule
+	macros   map[string][]string
+}
+
+func NewGitAttributes(rootPath string) *GitAttributes {
+	if absPath, err := filepath.Abs(rootPath); err == nil {
+		rootPath = absPath
+	}
+	if root := RepositoryRoot(rootPath); root != "" {
+		rootPath = root
+	}
+	return &GitAttributes{
+		rootPath: rootPath,
+		files:    make(map[string][]attributeRule),
+		macros:   make(map[string][]string),
+	}
+}
+
+func (g *GitAttributes) Lookup(path string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	relPath, ok := g.relative(path)
+	if !ok {
+		return attrs, nil
+	}
+
+	var infoRules []attributeRule
+	if info, err := os.Stat(filepath.Join(g.rootPath, ".git")); err == nil && info.IsDir() {
+		infoRules, err = g.rules(".git/info", filepath.Join(g.rootPath, ".git", "info", "attributes"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dirs := []string{""}
+	for i, c := range relPath {
+		if c == '/' {
+			dirs = append(dirs, relPath[:i])
+		}
+	}
+
+	for _, dir := range dirs {
+		rules, err := g.rules(dir, filepath.Join(g.rootPath, filepath.FromSlash(dir), ".gitattributes"))
+		if err != nil {
+			return nil, err
+		}
+		subPath := relPath
+		if dir != "" {
+			subPath = relPath[len(dir)+1:]
+		}
+		g.apply(attrs, rules, subPath)
+	}
+	g.apply(attrs, infoRules, relPath)
+	return attrs, nil
+}
+
+func (g *GitAttributes) relative(path string) (string, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	relPath, err := filepath.Rel(g.rootPath, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(relPath), true
+}
+
+func (g *GitAttributes) rules(key string, path string) ([]attributeRule, error) {
+	if rules, ok := g.files[key]; ok {
+		return rules, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var rules []attributeRule
+	for _, line := range strings.Split(string(content), "\n") {
+		pattern, fields, ok := splitAttributeLine(line)
+		switch {
+		case !ok || strings.HasPrefix(pattern, "!"):
+			continue
+		case strings.HasPrefix(pattern, "[attr]"):
+			if key == "" || key == ".git/info" {
+				g.macros[strings.TrimPrefix(pattern, "[attr]")] = fields
+			}
+			continue
+		case len(fields) == 0:
+			continue
+		}
+		matcher, ok := compileAttributePattern(pattern)
+		if !ok {
+			continue
+		}
+		rules = append(rules, attributeRule{
+			matcher: matcher,
+			attrs:   fields,
+		})
+	}
+	g.files[key] = rules
+	return rules, nil
+}
+
+func (g *GitAttributes) apply(attrs map[string]string, rules []attributeRule, path string) {
+	for _, rule := range rules {
+		if rule.matcher.MatchString(path) {
+			g.assign(attrs, rule.attrs, 0)
+		}
+	}
+}
+
+func (g *GitAttributes) assign(attrs map[string]string, assignments []string, depth int) {
+	for _, attr := range assignments {
+		switch {
+		case strings.HasPrefix(attr, "-"):
+			attrs[attr[1:]] = "false"
+		case strings.HasPrefix(attr, "!"):
+			delete(attrs, attr[1:])
+		case strings.Contains(attr, "="):
+			name, value, _ := strings.Cut(attr, "=")
+			attrs[name] = value
+		default:
+			attrs[attr] = "true"
+			if expansion, ok := g.macro(attr); ok && depth < maxMacroDepth {
+				g.assign(attrs, expansion, depth+1)
+			}
+		}
+	}
+}
+
+func (g *GitAttributes) macro(name string) ([]string, bool) {
+	if expansion, ok := g.macros[name]; ok {
+		return expansion, true
+	}
+	expansion, ok := builtinMacros[name]
+	return expansion, ok
+}
+
+func splitAttributeLine(line string) (string, []string, bool) {
+	line = strings.TrimLeft(line, " \t\r")
+	if strings.HasPrefix(line, "#") {
+		return "", nil, false
+	}
+	if !strings.HasPrefix(line, "\"") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return "", nil, false
+		}
+		return fields[0], fields[1:], true
+	}
+
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			pattern, err := strconv.Unquote(line[:i+1])
+			if err != nil {
+				return "", nil, false
+			}
+			return pattern, strings.Fields(line[i+1:]), true
+		}
+	}
+	return "", nil, false
+}
+
+func compileAttributePattern(pattern string) (*regexp.Regexp, bool) {
+	if pattern == "" || strings.HasSuffix(pattern, "/") {
+		return nil, false
+	}
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/") && (i == 0 || pattern[i-1] == '/'):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case c == '/' && pattern[i:] == "/**":
+			expr.WriteString("/.*")
+			i += 2
+		case c == '*':
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			class, length, ok := bracketClass(pattern[i:])
+			if !ok {
+				expr.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			expr.WriteString(class)
+			i += length - 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	matcher, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, false
+	}
+	return matcher, true
+}
+
+func bracketClass(pattern string) (string, int, bool) {
+	end := 1
+	negate := end < len(pattern) && (pattern[end] == '!' || pattern[end] == '^')
+	if negate {
+		end++
+	}
+	start := end
+	if end < len(pattern) && pattern[end] == ']' {
+		end++
+	}
+	for end < len(pattern) && pattern[end] != ']' {
+		end++
+	}
+	if end >= len(pattern) {
+		return "", 0, false
+	}
+
+	var class strings.Builder
+	class.WriteString("[")
+	if negate {
+		class.WriteString("^/")
+	}
+	for _, c := range pattern[start:end] {
+		if c == '\\' || c == '[' || c == ']' || c == '^' {
+			class.WriteRune('\\')
+		}
+		class.WriteRune(c)
+	}
+	class.WriteString("]")
+	return class.String(), end + 1, true
+}
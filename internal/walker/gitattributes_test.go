@@ -0,0 +1,91 @@
+package walker
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGitAttributesLookup(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		".gitattributes":       "# generated code\n*.gen.go linguist-generated\ndocs/** linguist-documentation eol=lf\n*.go -linguist-vendored\n",
+		"api/.gitattributes":   "client.gen.go -linguist-generated\n*.go !linguist-vendored\n",
+		".git/info/attributes": "[attr]thirdparty linguist-vendored -nocmt\napi/keep.go linguist-generated=false\n",
+		"lib/.gitattributes":   "[attr]ignored nocmt\n*.c thirdparty\n*.png binary\n*.h thirdparty !nocmt\n*.s ignored\n",
+	})
+
+	attributes := NewGitAttributes(tempDir)
+	tests := []struct {
+		path string
+		want map[string]string
+	}{
+		{"main.go", map[string]string{"linguist-vendored": "false"}},
+		{"models.gen.go", map[string]string{"linguist-generated": "true", "linguist-vendored": "false"}},
+		{"docs/guide.md", map[string]string{"linguist-documentation": "true", "eol": "lf"}},
+		{"api/client.gen.go", map[string]string{"linguist-generated": "false"}},
+		{"api/server.gen.go", map[string]string{"linguist-generated": "true"}},
+		{"api/keep.go", map[string]string{"linguist-generated": "false"}},
+		{"lib/zlib.c", map[string]string{"thirdparty": "true", "linguist-vendored": "true", "nocmt": "false"}},
+		{"lib/zlib.h", map[string]string{"thirdparty": "true", "linguist-vendored": "true"}},
+		{"lib/logo.png", map[string]string{"binary": "true", "diff": "false", "merge": "false", "text": "false"}},
+		{"lib/start.s", map[string]string{"ignored": "true"}},
+	}
+
+	for _, tt := range tests {
+		got, err := attributes.Lookup(filepath.Join(tempDir, tt.path))
+		if err != nil {
+			t.Fatalf("Lookup(%s) error = %v", tt.path, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Lookup(%s) = %v, want %v", tt.path, got, tt.want)
+			continue
+		}
+		for name, value := range tt.want {
+			if got[name] != value {
+				t.Errorf("Lookup(%s)[%s] = %q, want %q", tt.path, name, got[name], value)
+			}
+		}
+	}
+}
+
+func TestGitAttributesPatterns(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		".gitattributes": "docs/ -nocmt\nbuild -nocmt\n!*.go -nocmt\n\\!important.go -nocmt\n\"my file.go\" -nocmt\n\"#hash.go\" -nocmt\n/root.go nocmt=skip\nsrc/**/*.pb.go nocmt=skip\n**/fixtures/** nocmt=selective-only\nlog[0-9].go nocmt=skip\nv?.go nocmt=skip\n",
+	})
+
+	attributes := NewGitAttributes(tempDir)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"docs/guide.go", ""},
+		{"build/out.go", ""},
+		{"build", "false"},
+		{"main.go", ""},
+		{"!important.go", "false"},
+		{"my file.go", "false"},
+		{"#hash.go", "false"},
+		{"root.go", "skip"},
+		{"sub/root.go", ""},
+		{"src/api.pb.go", "skip"},
+		{"src/a/b/api.pb.go", "skip"},
+		{"lib/src/api.pb.go", ""},
+		{"fixtures/a.go", "selective-only"},
+		{"test/fixtures/deep/a.go", "selective-only"},
+		{"log1.go", "skip"},
+		{"logs.go", ""},
+		{"v1.go", "skip"},
+		{"v10.go", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := attributes.Lookup(filepath.Join(tempDir, tt.path))
+		if err != nil {
+			t.Fatalf("Lookup(%s) error = %v", tt.path, err)
+		}
+		if got["nocmt"] != tt.want {
+			t.Errorf("Lookup(%s)[nocmt] = %q, want %q", tt.path, got["nocmt"], tt.want)
+		}
+	}
+}
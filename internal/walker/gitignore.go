@@ -75,6 +75,8 @@ var DefaultIgnorePatterns = []string{
 	"*.old",
 }
 
+const NocmtIgnoreFile = ".nocmtignore"
+
 type patternInfo struct {
 	pattern    string
 	isNegated  bool
@@ -112,23 +114,69 @@ func (g *GitIgnoreChecker) IsIgnored(path string) bool {
 }
 
 type HierarchicalGitIgnoreChecker struct {
+	fileName       string
 	gitignoreFiles map[string]*gitignore.GitIgnore
 	rootPath       string
 	patterns       map[string][]patternInfo
 	defaultIgnorer *gitignore.GitIgnore
+	loaded         map[string]bool
 }
 
 func NewHierarchicalGitIgnoreChecker(rootPath string) (*HierarchicalGitIgnoreChecker, error) {
-	checker := &HierarchicalGitIgnoreChecker{
+	checker := newHierarchicalChecker(rootPath, ".gitignore", DefaultIgnorePatterns)
+	if err := checker.findAllGitignoreFiles(rootPath); err != nil {
+		return nil, err
+	}
+	return checker, nil
+}
+
+func NewNocmtIgnoreChecker(rootPath string) *HierarchicalGitIgnoreChecker {
+	if absPath, err := filepath.Abs(rootPath); err == nil {
+		rootPath = absPath
+	}
+	if root := RepositoryRoot(rootPath); root != "" {
+		rootPath = root
+	}
+	checker := newHierarchicalChecker(rootPath, NocmtIgnoreFile, nil)
+	checker.loaded = make(map[string]bool)
+	return checker
+}
+
+func IsNocmtIgnored(path string) (bool, error) {
+	return NewNocmtIgnoreChecker(filepath.Dir(path)).Ignored(path)
+}
+
+func newHierarchicalChecker(rootPath string, fileName string, defaults []string) *HierarchicalGitIgnoreChecker {
+	return &HierarchicalGitIgnoreChecker{
+		fileName:       fileName,
 		gitignoreFiles: make(map[string]*gitignore.GitIgnore),
 		rootPath:       rootPath,
 		patterns:       make(map[string][]patternInfo),
-		defaultIgnorer: gitignore.CompileIgnoreLines(DefaultIgnorePatterns...),
+		defaultIgnorer: gitignore.CompileIgnoreLines(defaults...),
 	}
-	if err := checker.findAllGitignoreFiles(rootPath); err != nil {
-		return nil, err
+}
+
+func (h *HierarchicalGitIgnoreChecker) loadAncestors(relPath string) error {
+	dirs := []string{""}
+	for i, c := range relPath {
+		if c == '/' {
+			dirs = append(dirs, relPath[:i])
+		}
 	}
-	return checker, nil
+	for _, dir := range dirs {
+		if h.loaded[dir] {
+			continue
+		}
+		h.loaded[dir] = true
+		path := filepath.Join(h.rootPath, filepath.FromSlash(dir), h.fileName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := h.processGitignoreFile(path, dir); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (h *HierarchicalGitIgnoreChecker) findAllGitignoreFiles(rootPath string) error {
@@ -136,7 +184,7 @@ func (h *HierarchicalGitIgnoreChecker) findAllGitignoreFiles(rootPath string) er
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && d.Name() == ".gitignore" {
+		if !d.IsDir() && d.Name() == h.fileName {
 			dir := filepath.Dir(path)
 			relDir, err := h.getRelativeDir(dir)
 			if err != nil {
@@ -232,14 +280,33 @@ func (h *HierarchicalGitIgnoreChecker) hasExplicitUnignorePattern(path, relPath
 }
 
 func (h *HierarchicalGitIgnoreChecker) IsIgnored(path string) bool {
+	ignored, _ := h.Ignored(path)
+	return ignored
+}
+
+func (h *HierarchicalGitIgnoreChecker) Ignored(path string) (bool, error) {
+	if filepath.IsAbs(h.rootPath) {
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+	}
 	relPath, err := filepath.Rel(h.rootPath, path)
 	if err != nil {
-		return false
+		return false, nil
 	}
 	relPath = filepath.ToSlash(relPath)
+	if relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return false, nil
+	}
+
+	if h.loaded != nil {
+		if err := h.loadAncestors(relPath); err != nil {
+			return false, err
+		}
+	}
 
 	if h.hasExplicitUnignorePattern(path, relPath) {
-		return false
+		return false, nil
 	}
 
 	dirs := h.findApplicableDirectories(relPath)
@@ -261,8 +328,8 @@ func (h *HierarchicalGitIgnoreChecker) IsIgnored(path string) bool {
 		}
 		matched, pattern := ignorer.MatchesPathHow(subPath)
 		if matched && pattern != nil {
-			return !(pattern.Negate)
+			return !(pattern.Negate), nil
 		}
 	}
-	return h.defaultIgnorer.MatchesPath(relPath)
+	return h.defaultIgnorer.MatchesPath(relPath), nil
 }
@@ -0,0 +1,52 @@
+package walker
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	NocmtSkip          = "skip"
+	NocmtSelectiveOnly = "selective-only"
+)
+
+type FilePolicy struct {
+	attributes       *GitAttributes
+	includeGenerated bool
+}
+
+func NewFilePolicy(rootPath string, includeGenerated bool) *FilePolicy {
+	return &FilePolicy{attributes: NewGitAttributes(rootPath), includeGenerated: includeGenerated}
+}
+
+func (p *FilePolicy) SkipReason(path string, content []byte, selective bool) (string, error) {
+	attrs, err := p.attributes.Lookup(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read .gitattributes for %s: %w", path, err)
+	}
+
+	switch value := attrs["nocmt"]; value {
+	case "":
+	case "true":
+		return "", nil
+	case "false":
+		return "-nocmt in .gitattributes", nil
+	case NocmtSkip:
+		return "nocmt=skip in .gitattributes", nil
+	case NocmtSelectiveOnly:
+		if !selective {
+			return "nocmt=selective-only in .gitattributes", nil
+		}
+	default:
+		return "", fmt.Errorf("invalid nocmt attribute %q for %s: expected nocmt, -nocmt, nocmt=%s or nocmt=%s", value, path, NocmtSkip, NocmtSelectiveOnly)
+	}
+
+	if p.includeGenerated {
+		return "", nil
+	}
+	relPath, ok := p.attributes.relative(path)
+	if !ok {
+		relPath = filepath.ToSlash(filepath.Base(path))
+	}
+	return generatedReason(relPath, content, attrs), nil
+}
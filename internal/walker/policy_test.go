@@ -0,0 +1,86 @@
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilePolicyNocmtAttribute(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		".gitattributes":    "[attr]handwritten nocmt -linguist-generated\ndocs/** -nocmt\n*.pb.go nocmt=skip\nlegacy/** nocmt=selective-only\nlegacy/keep.go !nocmt\ngen/** handwritten\nbad/** nocmt=sometimes\n",
+		"main.go":           "package main\n",
+		"docs/example.go":   "package docs\n",
+		"api/service.pb.go": "package api\n",
+		"legacy/old.go":     "package legacy\n",
+		"legacy/keep.go":    "package legacy\n",
+		"gen/models.go":     "// Code generated by tool. DO NOT EDIT.\npackage gen\n",
+		"vendor/lib/lib.go": "package lib\n",
+		"bad/file.go":       "package bad\n",
+	}
+	writeTestFiles(t, tempDir, files)
+
+	policy := NewFilePolicy(tempDir, false)
+	tests := []struct {
+		path      string
+		selective bool
+		want      string
+	}{
+		{"main.go", false, ""},
+		{"docs/example.go", true, "-nocmt in .gitattributes"},
+		{"api/service.pb.go", false, "nocmt=skip in .gitattributes"},
+		{"legacy/old.go", false, "nocmt=selective-only in .gitattributes"},
+		{"legacy/old.go", true, ""},
+		{"legacy/keep.go", false, ""},
+		{"gen/models.go", false, ""},
+		{"vendor/lib/lib.go", true, "inside vendored directory vendor/"},
+	}
+
+	for _, tt := range tests {
+		got, err := policy.SkipReason(filepath.Join(tempDir, tt.path), []byte(files[tt.path]), tt.selective)
+		if err != nil {
+			t.Fatalf("SkipReason(%s) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("SkipReason(%s, selective=%v) = %q, want %q", tt.path, tt.selective, got, tt.want)
+		}
+	}
+
+	if _, err := policy.SkipReason(filepath.Join(tempDir, "bad/file.go"), nil, false); err == nil || !strings.Contains(err.Error(), "sometimes") {
+		t.Errorf("SkipReason() error = %v, want an invalid nocmt value error", err)
+	}
+
+	included, err := NewFilePolicy(tempDir, true).SkipReason(filepath.Join(tempDir, "docs/example.go"), nil, false)
+	if err != nil || included != "-nocmt in .gitattributes" {
+		t.Errorf("SkipReason() with generated files included = %q, %v, want the nocmt attribute to apply", included, err)
+	}
+}
+
+func TestProcessRepositoryHonorsNocmtAttribute(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		".gitattributes": "legacy/** nocmt=selective-only\n",
+		"main.go":        "package main\n\n// Run starts the server.\nfunc Run() {}\n",
+		"legacy/old.go":  "package legacy\n\n// Old does old things.\nfunc Old() {}\n",
+	}
+	writeTestFiles(t, tempDir, files)
+
+	integration := NewProcessorIntegration(ProcessorConfig{PreserveDirectives: true})
+	if err := integration.ProcessRepository(tempDir); err != nil {
+		t.Fatalf("ProcessRepository() error = %v", err)
+	}
+
+	legacy, err := os.ReadFile(filepath.Join(tempDir, "legacy/old.go"))
+	if err != nil {
+		t.Fatalf("Failed to read legacy/old.go: %v", err)
+	}
+	if string(legacy) != files["legacy/old.go"] {
+		t.Errorf("legacy/old.go should only be cleaned in staged runs, got:\n%s", legacy)
+	}
+	processed, _, _ := integration.GetStats()
+	if processed != 1 {
+		t.Errorf("processed = %d, want 1", processed)
+	}
+}
@@ -14,15 +14,19 @@ type ProcessorConfig struct {
 	DryRun             bool
 	Verbose            bool
 	Force              bool
+	CollectStats       bool
+	IncludeGenerated   bool
 	CommentConfig      *config.Config
 }
 
 type ProcessorIntegration struct {
 	factory        *processor.ProcessorFactory
 	config         ProcessorConfig
+	policy         *FilePolicy
 	processedCount int
 	skippedCount   int
 	errorCount     int
+	fileStats      []FileStats
 }
 
 func NewProcessorIntegration(config ProcessorConfig) *ProcessorIntegration {
@@ -38,6 +42,7 @@ func NewProcessorIntegration(config ProcessorConfig) *ProcessorIntegration {
 }
 
 func (p *ProcessorIntegration) ProcessRepository(rootPath string) error {
+	p.policy = NewFilePolicy(rootPath, p.config.IncludeGenerated)
 	walker := &Walker{}
 	return walker.Walk(rootPath, p.processFile)
 }
@@ -46,6 +51,10 @@ func (p *ProcessorIntegration) GetStats() (processed, skipped, errors int) {
 	return p.processedCount, p.skippedCount, p.errorCount
 }
 
+func (p *ProcessorIntegration) FileStats() []FileStats {
+	return p.fileStats
+}
+
 func (p *ProcessorIntegration) processFile(path string) error {
 	if p.config.CommentConfig != nil && p.config.CommentConfig.ShouldIgnoreFile(path) {
 		p.skippedCount++
@@ -77,10 +86,30 @@ func (p *ProcessorIntegration) processFile(path string) error {
 		return fmt.Errorf("failed to read %s: %w", path, err)
 	}
 
-	strippedContent, err := proc.StripComments(string(content))
+	reason, err := p.policy.SkipReason(path, content, false)
 	if err != nil {
 		p.errorCount++
-		return fmt.Errorf("failed to process %s: %w", path, err)
+		return err
+	}
+	if reason != "" {
+		p.skippedCount++
+		if p.config.Verbose {
+			fmt.Printf("Skipping %s: %s\n", path, reason)
+		}
+		return nil
+	}
+
+	strippedContent, err := proc.StripComments(string(content))
+	if err != nil {
+		return p.fail(fmt.Errorf("failed to process %s: %w", path, err))
+	}
+
+	if p.config.CollectStats {
+		stats, err := CollectFileStats(proc, path, string(content), strippedContent, p.config.CommentConfig)
+		if err != nil {
+			return p.fail(err)
+		}
+		p.fileStats = append(p.fileStats, stats)
 	}
 
 	if strippedContent == string(content) {
@@ -106,3 +135,12 @@ func (p *ProcessorIntegration) processFile(path string) error {
 	p.processedCount++
 	return nil
 }
+
+func (p *ProcessorIntegration) fail(err error) error {
+	p.errorCount++
+	if !p.config.CollectStats {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	return nil
+}
@@ -0,0 +1,111 @@
+package walker
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"nocmt/internal/config"
+	"nocmt/internal/processor"
+)
+
+type FileStats struct {
+	Path           string `json:"path,omitempty"`
+	Language       string `json:"language,omitempty"`
+	CodeLines      int    `json:"codeLines"`
+	CommentLines   int    `json:"commentLines"`
+	RemovableLines int    `json:"removableLines"`
+	BytesSaved     int    `json:"bytesSaved"`
+	TokensSaved    int    `json:"tokensSaved"`
+}
+
+func (s FileStats) Density() float64 {
+	if s.CodeLines+s.CommentLines == 0 {
+		return 0
+	}
+	return float64(s.CommentLines) / float64(s.CodeLines+s.CommentLines)
+}
+
+func (s *FileStats) Add(other FileStats) {
+	s.CodeLines += other.CodeLines
+	s.CommentLines += other.CommentLines
+	s.RemovableLines += other.RemovableLines
+	s.BytesSaved += other.BytesSaved
+	s.TokensSaved += other.TokensSaved
+}
+
+func CollectFileStats(proc processor.LanguageProcessor, path, content, stripped string, cfg *config.Config) (FileStats, error) {
+	comments, err := processor.InspectComments(proc, content, cfg)
+	if err != nil {
+		return FileStats{}, fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	return collectFileStats(path, proc.GetLanguageName(), content, stripped, comments), nil
+}
+
+func collectFileStats(path, language, original, stripped string, comments []processor.CommentInfo) FileStats {
+	lines := strings.Split(original, "\n")
+	commentOnly := make(map[int]bool)
+	removable := make(map[int]bool)
+	for _, comment := range comments {
+		if !isWholeLineComment(lines, comment) {
+			continue
+		}
+		for line := comment.StartLine; line <= comment.EndLine; line++ {
+			commentOnly[line] = true
+			if comment.Remove {
+				removable[line] = true
+			}
+		}
+	}
+
+	stats := FileStats{
+		Path:           path,
+		Language:       language,
+		CommentLines:   len(commentOnly),
+		RemovableLines: len(removable),
+		BytesSaved:     len(original) - len(stripped),
+		TokensSaved:    EstimateTokens(original) - EstimateTokens(stripped),
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" && !commentOnly[i+1] {
+			stats.CodeLines++
+		}
+	}
+	return stats
+}
+
+func isWholeLineComment(lines []string, comment processor.CommentInfo) bool {
+	if comment.StartLine < 1 || comment.EndLine > len(lines) {
+		return false
+	}
+	firstLine := strings.TrimSpace(lines[comment.StartLine-1])
+	lastLine := strings.TrimSpace(lines[comment.EndLine-1])
+	firstText, _, _ := strings.Cut(comment.Text, "\n")
+	lastText := comment.Text[strings.LastIndex(comment.Text, "\n")+1:]
+	return strings.HasPrefix(firstLine, strings.TrimSpace(firstText)) && strings.HasSuffix(lastLine, strings.TrimSpace(lastText))
+}
+
+func EstimateTokens(text string) int {
+	tokens := 0
+	wordLength := 0
+	flushWord := func() {
+		if wordLength > 0 {
+			tokens += (wordLength + 3) / 4
+			wordLength = 0
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			wordLength++
+		case unicode.IsSpace(r):
+			flushWord()
+		default:
+			flushWord()
+			tokens++
+		}
+	}
+	flushWord()
+	return tokens
+}
@@ -0,0 +1,78 @@
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 4},
+		{"// a", 3},
+		{"fmt.Println(x)", 7},
+		{"internationalization", 5},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCollectStats(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"main.go":     "package main\n\n// Run starts the server.\nfunc Run() {\n\tstart() // go\n}\n",
+		"lib/util.py": "# helper\n# more\ndef util():\n    return 1\n",
+		"README.md":   "# Title\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	integration := NewProcessorIntegration(ProcessorConfig{PreserveDirectives: true, DryRun: true, CollectStats: true})
+	if err := integration.ProcessRepository(tempDir); err != nil {
+		t.Fatalf("ProcessRepository() error = %v", err)
+	}
+
+	stats := make(map[string]FileStats)
+	for _, fileStats := range integration.FileStats() {
+		rel, _ := filepath.Rel(tempDir, fileStats.Path)
+		stats[rel] = fileStats
+	}
+	if len(stats) != 2 {
+		t.Fatalf("FileStats() = %v, want stats for the two source files", stats)
+	}
+
+	goStats := stats["main.go"]
+	if goStats.Language != "go" || goStats.CodeLines != 4 || goStats.CommentLines != 1 || goStats.RemovableLines != 1 {
+		t.Errorf("main.go stats = %+v", goStats)
+	}
+	if goStats.BytesSaved <= 0 || goStats.TokensSaved <= 0 {
+		t.Errorf("main.go should report savings, got %+v", goStats)
+	}
+
+	pyStats := stats[filepath.Join("lib", "util.py")]
+	if pyStats.CodeLines != 2 || pyStats.CommentLines != 2 || pyStats.Density() != 0.5 {
+		t.Errorf("util.py stats = %+v", pyStats)
+	}
+
+	for _, name := range []string{"main.go", filepath.Join("lib", "util.py")} {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil || string(content) != files[filepath.ToSlash(name)] {
+			t.Errorf("collecting stats should not modify %s", name)
+		}
+	}
+}
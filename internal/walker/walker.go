@@ -14,6 +14,7 @@ func (w *Walker) Walk(rootPath string, processor FileProcessor) error {
 	if err != nil {
 		return err
 	}
+	nocmtIgnores := NewNocmtIgnoreChecker(rootPath)
 
 	return filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -29,6 +30,14 @@ func (w *Walker) Walk(rootPath string, processor FileProcessor) error {
 				return filepath.SkipDir
 			}
 
+			ignored, err := nocmtIgnores.Ignored(path)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				return filepath.SkipDir
+			}
+
 			return nil
 		}
 
@@ -36,6 +45,14 @@ func (w *Walker) Walk(rootPath string, processor FileProcessor) error {
 			return nil
 		}
 
+		ignored, err := nocmtIgnores.Ignored(path)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			return nil
+		}
+
 		return processor(path)
 	})
-}
\ No newline at end of file
+}
@@ -3,6 +3,7 @@ package walker
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -187,3 +188,110 @@ func TestWalkerWithProcessorError(t *testing.T) {
 		t.Errorf("File before error file wasn't processed")
 	}
 }
+
+func TestWalkerWithNocmtIgnore(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		".nocmtignore":              "/test/\n*.gen.go\n!keep.gen.go\ndocs/**/examples/\nbuild\n",
+		"latest.go":                 "package main\n",
+		"test/helper.go":            "package test\n",
+		"pkg/test/helper.go":        "package test\n",
+		"models.gen.go":             "package main\n",
+		"keep.gen.go":               "package main\n",
+		"docs/api/examples/main.go": "package main\n",
+		"docs/api/guide.go":         "package api\n",
+		"build/out.go":              "package build\n",
+		"sub/.nocmtignore":          "local.go\n",
+		"sub/local.go":              "package sub\n",
+		"sub/other.go":              "package sub\n",
+		"local.go":                  "package main\n",
+	})
+
+	processedFiles := make(map[string]bool)
+	walker := &Walker{}
+	err := walker.Walk(tempDir, func(path string) error {
+		processedFiles[filepath.ToSlash(strings.TrimPrefix(path, tempDir+string(filepath.Separator)))] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+
+	for _, path := range []string{"latest.go", "pkg/test/helper.go", "keep.gen.go", "docs/api/guide.go", "sub/other.go", "local.go"} {
+		if !processedFiles[path] {
+			t.Errorf("Expected file to be processed but wasn't: %s", path)
+		}
+	}
+	for _, path := range []string{"test/helper.go", "models.gen.go", "docs/api/examples/main.go", "build/out.go", "sub/local.go"} {
+		if processedFiles[path] {
+			t.Errorf("Expected file NOT to be processed but was: %s", path)
+		}
+	}
+
+	ignored, err := IsNocmtIgnored(filepath.Join(tempDir, "sub", "local.go"))
+	if err != nil || !ignored {
+		t.Errorf("IsNocmtIgnored(sub/local.go) = %v, %v, want true", ignored, err)
+	}
+	ignored, err = IsNocmtIgnored(filepath.Join(tempDir, "latest.go"))
+	if err != nil || ignored {
+		t.Errorf("IsNocmtIgnored(latest.go) = %v, %v, want false", ignored, err)
+	}
+}
+
+func TestWalkerNocmtIgnoreFromSubdirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		".git/HEAD":           "ref: refs/heads/main\n",
+		".nocmtignore":        "/src/generated/\n*.fixture.go\n",
+		"src/main.go":         "package main\n",
+		"src/case.fixture.go": "package main\n",
+		"src/generated/a.go":  "package generated\n",
+	})
+
+	processedFiles := make(map[string]bool)
+	walker := &Walker{}
+	err := walker.Walk(filepath.Join(tempDir, "src"), func(path string) error {
+		processedFiles[filepath.ToSlash(strings.TrimPrefix(path, tempDir+string(filepath.Separator)))] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+
+	if !processedFiles["src/main.go"] {
+		t.Errorf("Expected src/main.go to be processed")
+	}
+	for _, path := range []string{"src/case.fixture.go", "src/generated/a.go"} {
+		if processedFiles[path] {
+			t.Errorf("Expected %s to be ignored by the repository root .nocmtignore", path)
+		}
+		ignored, err := IsNocmtIgnored(filepath.Join(tempDir, path))
+		if err != nil || !ignored {
+			t.Errorf("IsNocmtIgnored(%s) = %v, %v, want true", path, ignored, err)
+		}
+	}
+}
+
+func TestNocmtIgnoreCheckerLoadsAncestorsOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		".git/HEAD":          "ref: refs/heads/main\n",
+		".nocmtignore":       "*.tmp.go\n",
+		"a/b/.nocmtignore":   "local.go\n",
+		"a/b/local.go":       "package b\n",
+		"other/.nocmtignore": "*.go\n",
+		"other/x.go":         "package other\n",
+	})
+
+	checker := NewNocmtIgnoreChecker(filepath.Join(tempDir, "a"))
+	ignored, err := checker.Ignored(filepath.Join(tempDir, "a", "b", "local.go"))
+	if err != nil || !ignored {
+		t.Errorf("Ignored(a/b/local.go) = %v, %v, want true", ignored, err)
+	}
+	if _, ok := checker.gitignoreFiles["other"]; ok {
+		t.Errorf("other/.nocmtignore should not be loaded for a/b/local.go")
+	}
+	if _, ok := checker.gitignoreFiles[""]; !ok {
+		t.Errorf("the repository root .nocmtignore should be loaded")
+	}
+}
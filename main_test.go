@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -429,6 +430,408 @@ func UnstagedFunc() {
 	}
 }
 
+func TestConfigSubcommand(t *testing.T) {
+	tempDir := t.TempDir()
+	homeDir := filepath.Join(tempDir, "home")
+
+	configContent := `{"ignorePatterns": ["REVISIT", "WHY"], "fileIgnorePatterns": []}`
+	if err := os.WriteFile(filepath.Join(tempDir, ".nocmt.json"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = tempDir
+		cmd.Env = append(os.Environ(), "HOME="+homeDir)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("nocmt %v failed: %v\nOutput: %s", args, err, output)
+		}
+		return string(output)
+	}
+
+	output := run("-ignore", "NOTE", "config", "show-effective")
+	if !strings.Contains(output, "[.nocmt.json]") || !strings.Contains(output, "[cli]") {
+		t.Errorf("show-effective should list sources, got: %s", output)
+	}
+
+	output = run("config", "test", "// REVISIT: later")
+	if !strings.Contains(output, "REVISIT") || !strings.Contains(output, "kept") {
+		t.Errorf("config test should report the matching pattern, got: %s", output)
+	}
+
+	run("config", "remove", "REVISIT")
+	output = run("config", "list")
+	if strings.Contains(output, "REVISIT") || !strings.Contains(output, "WHY") {
+		t.Errorf("config remove should delete the pattern from .nocmt.json, got: %s", output)
+	}
+
+	output = run("config", "test", "// REVISIT: later")
+	if !strings.Contains(output, "would be removed") {
+		t.Errorf("removed pattern should no longer keep the comment, got: %s", output)
+	}
+
+	for _, comment := range []string{"// eslint-disable-next-line no-console", "// SPDX-License-Identifier: MIT"} {
+		output = run("config", "test", comment)
+		if !strings.Contains(output, "would be kept") {
+			t.Errorf("config test should keep %q like a real run does, got: %s", comment, output)
+		}
+	}
+	output = run("config", "test", "-lang", "go", "//go:generate stringer -type=Kind")
+	if !strings.Contains(output, "would be kept: directive") {
+		t.Errorf("config test should keep directives, got: %s", output)
+	}
+}
+
+func TestConfigTestKeepMarkers(t *testing.T) {
+	tempDir := t.TempDir()
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = tempDir
+		cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("nocmt %v failed: %v\nOutput: %s", args, err, output)
+		}
+		return string(output)
+	}
+
+	if output := run("config", "test", "// TODO: fix this"); !strings.Contains(output, "would be kept: task marker TODO") {
+		t.Errorf("config test should report the keep marker, got: %s", output)
+	}
+	if output := run("-only", "TODO", "config", "test", "// TODO: fix this"); !strings.Contains(output, "would be removed: remove pattern TODO") {
+		t.Errorf("a matching remove pattern should override the keep marker, got: %s", output)
+	}
+	if output := run("-no-default-keeps", "config", "test", "// TODO: fix this"); !strings.Contains(output, "would be removed") {
+		t.Errorf("-no-default-keeps should drop the default markers, got: %s", output)
+	}
+}
+
+func TestLintSubcommand(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "main.go")
+	content := `package main
+
+func main() {
+	// get user by id
+	user := getUserByID(id)
+	// Retries are capped to protect upstream
+	retry(user)
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "lint", testFile)
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Errorf("lint should exit with an error when it finds redundant comments")
+	}
+	if !strings.Contains(string(output), "main.go:4: redundant comment") || strings.Contains(string(output), "Retries") {
+		t.Errorf("lint should report only the redundant comment, got: %s", output)
+	}
+
+	modified, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(modified) != content {
+		t.Errorf("lint should not modify files")
+	}
+}
+
+func TestListSubcommand(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\n// TODO: later\nfunc main() {} // entry\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "zz_generated.go"), []byte("// Code generated by tool. DO NOT EDIT.\n\npackage main\n\n// generated helper\nfunc helper() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write generated file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("ignored/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .gitignore: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "ignored"), 0755); err != nil {
+		t.Fatalf("Failed to create ignored directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "ignored", "skip.go"), []byte("package skip\n\n// hidden\n"), 0644); err != nil {
+		t.Fatalf("Failed to write ignored file: %v", err)
+	}
+
+	binaryPath := filepath.Join(t.TempDir(), "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "-ignore", "TODO", "list", "-format", "jsonl")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("nocmt list failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("list should report two comments, one skipped file and nothing from gitignored files, got: %s", output)
+	}
+	if !strings.Contains(lines[0], `"remove":false`) || !strings.Contains(lines[0], `"reason":"ignore pattern TODO (cli)"`) {
+		t.Errorf("first comment should be kept by the ignore pattern, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"startLine":4`) || !strings.Contains(lines[1], `"remove":true`) {
+		t.Errorf("trailing comment should be removed, got: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"file":"zz_generated.go"`) || !strings.Contains(lines[2], `"skipped":true`) || !strings.Contains(lines[2], `"remove":false`) {
+		t.Errorf("generated file should be listed once as skipped, got: %s", lines[2])
+	}
+
+	cmd = exec.Command(binaryPath, "list", "zz_generated.go")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("nocmt list on a generated file failed: %v", err)
+	}
+	if strings.Contains(string(output), "remove") || !strings.Contains(string(output), "skip") {
+		t.Errorf("generated file should be shown as skipped, not as removals, got: %s", output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if err != nil || !strings.Contains(string(content), "// entry") {
+		t.Errorf("list should not modify files")
+	}
+}
+
+func TestStatsSubcommand(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\n// explain main\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "broken.go"), []byte("package main\n\n// half done\nfunc broken( {\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	binaryPath := filepath.Join(t.TempDir(), "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "stats", "-format", "json", tempDir)
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("nocmt stats failed: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "broken.go") {
+		t.Errorf("stats should warn about the file it cannot parse, got: %s", stderr.String())
+	}
+
+	var report struct {
+		Languages []struct {
+			Name           string `json:"name"`
+			CommentLines   int    `json:"commentLines"`
+			RemovableLines int    `json:"removableLines"`
+			TokensSaved    int    `json:"tokensSaved"`
+		} `json:"languages"`
+		Unparsable int `json:"unparsable"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("stats output is not JSON: %v\n%s", err, output)
+	}
+	if len(report.Languages) != 1 || report.Languages[0].Name != "go" || report.Languages[0].RemovableLines != 1 || report.Languages[0].TokensSaved == 0 {
+		t.Errorf("unexpected stats report: %s", output)
+	}
+	if report.Unparsable != 1 {
+		t.Errorf("expected the broken file to be counted as unparsable: %s", output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if err != nil || !strings.Contains(string(content), "// explain main") {
+		t.Errorf("stats should not modify files")
+	}
+}
+
+func TestHistorySubcommand(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	commit := func(date, content string) {
+		if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		for _, args := range [][]string{{"add", "main.go"}, {"commit", "-q", "-m", "update"}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = tempDir
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, output)
+			}
+		}
+	}
+	commit("2024-01-10T12:00:00Z", "package main\n\nfunc main() {}\n")
+	extra := map[string]string{
+		"zz_generated.go": "// Code generated by tool. DO NOT EDIT.\n\npackage main\n\n// Build builds.\nfunc Build() {}\n",
+		"legacy.go":       "package main\n\n// Old explains.\nfunc Old() {}\n",
+		".nocmtignore":    "legacy.go\n",
+		"broken.go":       "package main\n\n// half done\nfunc broken( {\n",
+	}
+	for name, content := range extra {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	addCmd := exec.Command("git", "add", ".")
+	addCmd.Dir = tempDir
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, output)
+	}
+	commit("2024-02-10T12:00:00Z", "package main\n\n// explain main\n// in two lines\nfunc main() {}\n")
+
+	binaryPath := filepath.Join(t.TempDir(), "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "history", "-since", "2023-12-01", "-step", "monthly", "-authors", "-format", "json")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("nocmt history failed: %v", err)
+	}
+
+	var report struct {
+		Points []struct {
+			Date  string `json:"date"`
+			Total struct {
+				CommentLines int `json:"commentLines"`
+			} `json:"total"`
+			Skipped    int `json:"skipped"`
+			Unparsable int `json:"unparsable"`
+			Authors    []struct {
+				Name              string `json:"name"`
+				AddedCommentLines int    `json:"addedCommentLines"`
+			} `json:"authors"`
+		} `json:"points"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("history output is not JSON: %v\n%s", err, output)
+	}
+	if len(report.Points) != 2 {
+		t.Fatalf("expected one point per month, got: %s", output)
+	}
+	if report.Points[0].Date != "2024-01-01" || report.Points[0].Total.CommentLines != 0 {
+		t.Errorf("unexpected first point: %s", output)
+	}
+	if report.Points[1].Date != "2024-02-01" || report.Points[1].Total.CommentLines != 2 {
+		t.Errorf("unexpected second point: %s", output)
+	}
+	if report.Points[1].Skipped != 2 || report.Points[1].Unparsable != 1 {
+		t.Errorf("expected the generated and .nocmtignore'd files to be skipped and broken.go to be unparsable: %s", output)
+	}
+	if len(report.Points[1].Authors) != 1 || report.Points[1].Authors[0].Name != "Test User" || report.Points[1].Authors[0].AddedCommentLines != 2 {
+		t.Errorf("expected the added comments to be attributed to Test User: %s", output)
+	}
+}
+
+func TestCommentBudget(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	content := `package main
+
+// Run starts everything.
+// It first prepares the state.
+// Then it runs the loop.
+func Run() {
+	prepare()
+	loop()
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "run.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".nocmt.json"), []byte(`{"budget": {"maxAddedCommentRatio": 0.15}}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	stageCmd := exec.Command("git", "add", "run.go")
+	stageCmd.Dir = tempDir
+	if err := stageCmd.Run(); err != nil {
+		t.Fatalf("Failed to stage file: %v", err)
+	}
+
+	binaryPath := filepath.Join(t.TempDir(), "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, "-staged")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected nocmt to fail when the comment budget is exceeded:\n%s", output)
+	}
+	if !strings.Contains(string(output), "Comment budget exceeded") || !strings.Contains(string(output), "3 of 8 added lines are comments") {
+		t.Errorf("unexpected budget report:\n%s", output)
+	}
+
+	after, err := os.ReadFile(filepath.Join(tempDir, "run.go"))
+	if err != nil || string(after) != content {
+		t.Errorf("the budget check should not modify files")
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "broken.go"), []byte("package main\n\n// half done\nfunc Broken( {\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	stageCmd = exec.Command("git", "add", "broken.go")
+	stageCmd.Dir = tempDir
+	if err := stageCmd.Run(); err != nil {
+		t.Fatalf("Failed to stage file: %v", err)
+	}
+
+	cmd = exec.Command(binaryPath, "-staged", "-max-comment-ratio", "0.5")
+	cmd.Dir = tempDir
+	cmd.Env = append(os.Environ(), "HOME="+filepath.Join(tempDir, "home"))
+	output, err = cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(output), "Comment budget met") {
+		t.Errorf("expected the CLI ratio to override the config: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "Warning: Skipping broken.go") {
+		t.Errorf("expected a warning for the file that cannot be parsed:\n%s", output)
+	}
+}
+
 func initGitRepo(t *testing.T, dir string) {
 	cmd := exec.Command("git", "init")
 	cmd.Dir = dir
@@ -448,3 +851,224 @@ func initGitRepo(t *testing.T, dir string) {
 		t.Fatalf("Failed to configure git name: %v", err)
 	}
 }
+
+func TestNoDefaultKeepsFlag(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	testContent := `package test
+
+// TODO: handle errors
+func TestFunc() {
+    // FIXME
+    println("Hello")
+}
+`
+	keptFile := filepath.Join(tempDir, "kept.go")
+	removedFile := filepath.Join(tempDir, "removed.go")
+	for _, path := range []string{keptFile, removedFile} {
+		if err := os.WriteFile(path, []byte(testContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, keptFile)
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run nocmt: %v\n%s", err, output)
+	}
+	kept, err := os.ReadFile(keptFile)
+	if err != nil {
+		t.Fatalf("Failed to read processed file: %v", err)
+	}
+	if !strings.Contains(string(kept), "// TODO: handle errors") || !strings.Contains(string(kept), "// FIXME") {
+		t.Errorf("Task markers should be kept by default, got:\n%s", kept)
+	}
+
+	cmd = exec.Command(binaryPath, "-no-default-keeps", removedFile)
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run nocmt with -no-default-keeps: %v\n%s", err, output)
+	}
+	removed, err := os.ReadFile(removedFile)
+	if err != nil {
+		t.Fatalf("Failed to read processed file: %v", err)
+	}
+	if strings.Contains(string(removed), "//") {
+		t.Errorf("Task markers should be removed with -no-default-keeps, got:\n%s", removed)
+	}
+}
+
+func TestStrictPresetRemovesTaskMarkers(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	testContent := `package test
+
+// TODO: handle errors
+func TestFunc() {
+    // FIXME
+    println("Hello")
+}
+`
+	testFile := filepath.Join(tempDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".nocmt.json"), []byte(`{"extends": ["strict"]}`), 0644); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	cmd := exec.Command(binaryPath, testFile)
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run nocmt: %v\n%s", err, output)
+	}
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read processed file: %v", err)
+	}
+	if strings.Contains(string(content), "//") {
+		t.Errorf("Task markers should be removed with the strict preset, got:\n%s", content)
+	}
+}
+
+func TestStagedFilesHonorNocmtAttribute(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	content := "package test\n\n// This comment is new\nfunc Func() {}\n"
+	files := map[string]string{
+		".gitattributes":  "docs/** -nocmt\nlegacy/** nocmt=selective-only\n",
+		"docs/example.go": content,
+		"legacy/old.go":   content,
+	}
+	for name, data := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	stageCmd := exec.Command("git", "add", ".")
+	stageCmd.Dir = tempDir
+	if err := stageCmd.Run(); err != nil {
+		t.Fatalf("Failed to stage files: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	runCmd := exec.Command(binaryPath, "-staged", "-verbose")
+	runCmd.Dir = tempDir
+	output, err := runCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run nocmt with -staged flag: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "Skipping docs/example.go: -nocmt in .gitattributes") {
+		t.Errorf("Expected a skip reason for docs/example.go, got:\n%s", output)
+	}
+
+	docs, err := os.ReadFile(filepath.Join(tempDir, "docs/example.go"))
+	if err != nil {
+		t.Fatalf("Failed to read docs/example.go: %v", err)
+	}
+	if string(docs) != content {
+		t.Errorf("docs/example.go should be left alone, got:\n%s", docs)
+	}
+
+	legacy, err := os.ReadFile(filepath.Join(tempDir, "legacy/old.go"))
+	if err != nil {
+		t.Fatalf("Failed to read legacy/old.go: %v", err)
+	}
+	if strings.Contains(string(legacy), "This comment is new") {
+		t.Errorf("legacy/old.go should be cleaned in staged runs, got:\n%s", legacy)
+	}
+}
+
+func TestNocmtIgnoreFile(t *testing.T) {
+	tempDir := t.TempDir()
+	initGitRepo(t, tempDir)
+
+	content := "package test\n\n// This comment is new\nfunc Func() {}\n"
+	files := map[string]string{
+		".nocmtignore":      "/fixtures/\n",
+		"fixtures/input.go": content,
+		"latest.go":         content,
+	}
+	for name, data := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	binaryPath := filepath.Join(tempDir, "nocmt-test")
+	buildCmd := exec.Command("go", "build", "-o", binaryPath, "./cmd/nocmt")
+	if err := buildCmd.Run(); err != nil {
+		t.Fatalf("Failed to build nocmt binary: %v", err)
+	}
+
+	singleCmd := exec.Command(binaryPath, filepath.Join("fixtures", "input.go"))
+	singleCmd.Dir = tempDir
+	output, err := singleCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run nocmt on a single file: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "matches .nocmtignore") {
+		t.Errorf("Expected a .nocmtignore skip message, got:\n%s", output)
+	}
+
+	stageCmd := exec.Command("git", "add", ".")
+	stageCmd.Dir = tempDir
+	if err := stageCmd.Run(); err != nil {
+		t.Fatalf("Failed to stage files: %v", err)
+	}
+
+	runCmd := exec.Command(binaryPath, "-staged", "-verbose")
+	runCmd.Dir = tempDir
+	output, err = runCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run nocmt with -staged flag: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "Skipping fixtures/input.go: matches .nocmtignore") {
+		t.Errorf("Expected a skip reason for fixtures/input.go, got:\n%s", output)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(tempDir, "fixtures", "input.go"))
+	if err != nil {
+		t.Fatalf("Failed to read fixtures/input.go: %v", err)
+	}
+	if string(fixture) != content {
+		t.Errorf("fixtures/input.go should be left alone, got:\n%s", fixture)
+	}
+
+	latest, err := os.ReadFile(filepath.Join(tempDir, "latest.go"))
+	if err != nil {
+		t.Fatalf("Failed to read latest.go: %v", err)
+	}
+	if strings.Contains(string(latest), "This comment is new") {
+		t.Errorf("latest.go should be cleaned, got:\n%s", latest)
+	}
+}
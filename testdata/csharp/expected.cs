@@ -1,3 +1,6 @@
+// Copyright 2025 Example Corp
+// Licensed under MIT
+
 using System;
 using System.Collections.Generic;
 
@@ -1,3 +1,6 @@
+// Copyright 2025 Example
+// License: MIT
+
 //go:build linux && !windows
 // +build linux,!windows
 
@@ -21,10 +21,13 @@ public class EdgeCases {
     
     private String blockEnd;
     
+    // TODO: This is a TODO comment that should be kept
     private String todoComment;
     
+    // FIXME: This is a FIXME comment
     private String fixmeComment;
     
+    // XXX: This is an XXX comment
     private String xxxComment;
     
     private String unicodeComment;
@@ -32,7 +32,7 @@ public class EdgeCases {
     // Comment with */ block end inside
     private String blockEnd;
     
-    // TODO: This is a TODO comment that should be removed
+    // TODO: This is a TODO comment that should be kept
     private String todoComment;
     
     // FIXME: This is a FIXME comment
@@ -48,6 +48,8 @@ public class ExampleService {
             System.out.println(service.greetUser(user));
         }
 
+        // TODO: Add real business logic here
+
         service.shutdown();
     }
 }
@@ -0,0 +1,23 @@
+#!/usr/bin/env kotlin
+// The build needs the generated sources, so suppress the warnings.
+@file:Suppress("UnstableApiUsage") // AGP APIs are incubating
+
+plugins {
+    id("com.android.application")
+    kotlin("android")
+}
+
+// region Dependencies
+dependencies {
+    // ktlint-disable
+    implementation("androidx.core:core-ktx:1.12.0")
+    // ktlint-enable
+    //noinspection GradleDependency
+    implementation("com.squareup.okhttp3:okhttp:3.14.9")
+    testImplementation(kotlin("test"))
+}
+// endregion
+
+tasks.register("hello") {
+    doLast { println("hello") }
+}
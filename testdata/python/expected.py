@@ -14,6 +14,7 @@ Multiline string assigned to x
 
 def func(a: int, b: int) -> int:
     """Function docstring — should be removed"""
+    # TODO: something
     return a + b
 
 # type: list[int]
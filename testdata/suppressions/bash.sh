@@ -0,0 +1,6 @@
+#!/bin/bash
+# remove me
+# shellcheck disable=SC2086
+echo $1
+# shellcheck source=lib.sh
+. ./lib.sh
@@ -0,0 +1,25 @@
+#include <cstdio>
+
+// remove me
+int legacy(int *p) { // NOLINT(readability-non-const-parameter)
+    return *p;
+}
+
+// NOLINTNEXTLINE(cppcoreguidelines-avoid-magic-numbers)
+int answer = 42;
+
+// clang-format off
+int matrix[] = {1, 0,
+                0, 1};
+// clang-format on
+
+// cppcheck-suppress unusedFunction
+void unused() {}
+
+void debug() { // LCOV_EXCL_LINE
+    std::puts("debug"); // remove me
+}
+
+// GCOVR_EXCL_START
+void skipped() {}
+// GCOVR_EXCL_STOP
@@ -0,0 +1,16 @@
+using System;
+
+// remove me
+public class Program
+{
+    // ReSharper disable once UnusedMember.Local
+    private void Unused() { }
+
+    // ReSharper restore UnusedMember.Local
+    // dotCover disable
+    public static void Main()
+    {
+        Console.WriteLine("hi"); // remove me
+    }
+    // dotCover enable
+}
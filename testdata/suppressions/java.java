@@ -0,0 +1,21 @@
+// CHECKSTYLE:OFF
+// remove me
+public class Example {
+    // CHECKSTYLE:ON
+    private int value; // NOSONAR
+
+    //noinspection unchecked
+    private Object raw;
+
+    // @formatter:off
+    private int[] matrix = {1, 0,
+                            0, 1};
+    // @formatter:on
+
+    // spotless:off
+    private int spaced   =   1;
+    // spotless:on
+
+    private int unused; // NOPMD
+    private int other; // remove me
+}
@@ -0,0 +1,23 @@
+/* eslint-disable no-console */
+/* eslint-env node */
+/* eslint no-alert: "off" */
+// remove me
+const a = 1;
+// eslint-disable-next-line no-unused-vars
+const b = 2;
+// prettier-ignore
+const matrix = [1,0,
+                0,1];
+/* istanbul ignore next */
+function untested() {}
+/* c8 ignore next */
+function alsoUntested() {}
+/* v8 ignore start */
+function ignored() {}
+/* v8 ignore stop */
+/* jshint esversion: 6 */
+// $FlowFixMe
+const c = a + b;
+// biome-ignore lint/style/useConst: legacy code
+let d = c; // remove me
+console.log(d, matrix, untested, alsoUntested, ignored);
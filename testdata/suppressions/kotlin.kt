@@ -0,0 +1,20 @@
+// remove me
+package example
+
+// ktlint-disable no-wildcard-imports
+import kotlin.collections.*
+// ktlint-enable no-wildcard-imports
+
+// region Helpers
+// detekt:disable MagicNumber
+fun answer() = 42
+// endregion
+
+//noinspection SpellCheckingInspection
+val teh = "typo"
+
+// @formatter:off
+val matrix = listOf(1, 0,
+                    0, 1)
+// @formatter:on
+val unused = 1 // remove me
@@ -0,0 +1,17 @@
+<?php
+// remove me
+// phpcs:ignore Generic.Files.LineLength
+$long = "a very long line";
+// phpcs:disable
+$a = 1;
+// phpcs:enable
+// @codingStandardsIgnoreLine
+$b = 2;
+/** @phpstan-ignore-next-line */
+$c = undefined_function();
+/** @psalm-suppress UndefinedFunction */
+$d = other_function();
+/** @phan-suppress PhanUndeclaredFunction */
+$e = third_function();
+/** @codeCoverageIgnore */
+function untested() {} // remove me
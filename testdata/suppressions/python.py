@@ -0,0 +1,18 @@
+import os  # noqa: F401
+# remove me
+value = compute()  # type: ignore[name-defined]
+other = value.missing  # pyright: ignore[reportAttributeAccessIssue]
+
+
+def handler():  # pylint: disable=too-many-branches
+    if os.environ.get("DEBUG"):  # pragma: no cover
+        pass
+
+
+# fmt: off
+matrix = [1, 0,
+          0, 1]
+# fmt: on
+import sys  # isort: skip
+token = "hard-coded"  # nosec
+count = 1  # remove me
@@ -0,0 +1,11 @@
+// swiftlint:disable force_cast
+// remove me
+let value = object as! String
+// swiftlint:enable force_cast
+
+// swiftformat:disable all
+let spaced   =   1
+// swiftformat:enable all
+
+// periphery:ignore
+func unused() {} // remove me
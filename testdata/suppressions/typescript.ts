@@ -0,0 +1,22 @@
+// @ts-nocheck
+// remove me
+// tslint:disable-next-line:no-any
+const value: any = 1;
+// @ts-expect-error wrong type on purpose
+const count: number = "one";
+// eslint-disable-next-line @typescript-eslint/no-explicit-any
+const other: any = value;
+// prettier-ignore
+const pairs = [1,2,  3,4];
+/* istanbul ignore next */
+export function untested(): void {} // remove me
+/* c8 ignore next */
+export function alsoUntested(): void {}
+/* v8 ignore next */
+export function ignored(): void {}
+/* jshint esversion: 6 */
+// $FlowFixMe
+const flow = count;
+// biome-ignore lint/style/useConst: legacy code
+let late = flow;
+console.log(count, other, pairs, late);